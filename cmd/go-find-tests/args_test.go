@@ -25,11 +25,7 @@ var parsePositionTests = map[string]struct {
 	},
 	"no_line_or_col": {
 		providedArg: "./cover/profile.go",
-		expectedPos: &pos{
-			file: "./cover/profile.go",
-			line: 154,
-			col:  0,
-		},
+		expectErr:   true,
 	},
 	"std_lib_file": {
 		providedArg: "fmt/errors.go:17.52",
@@ -39,6 +35,44 @@ var parsePositionTests = map[string]struct {
 			col:  52,
 		},
 	},
+	"space_in_path": {
+		providedArg: "./my project/main.go:10.2",
+		expectedPos: &pos{
+			file: "./my project/main.go",
+			line: 10,
+			col:  2,
+		},
+	},
+	"module_cache_file": {
+		providedArg: "/go/pkg/mod/github.com/pkg/errors@v0.9.1/errors.go:101",
+		expectedPos: &pos{
+			file: "/go/pkg/mod/github.com/pkg/errors@v0.9.1/errors.go",
+			line: 101,
+		},
+	},
+	"plus_in_path": {
+		providedArg: "./c++/bind.go:7.1",
+		expectedPos: &pos{
+			file: "./c++/bind.go",
+			line: 7,
+			col:  1,
+		},
+	},
+	"home_relative_path": {
+		providedArg: "~/src/app/main.go:3",
+		expectedPos: &pos{
+			file: "~/src/app/main.go",
+			line: 3,
+		},
+	},
+	"not_go_file": {
+		providedArg: "./cover/profileXgo:154",
+		expectErr:   true,
+	},
+	"trailing_text": {
+		providedArg: "./cover/profile.go:154.11x",
+		expectErr:   true,
+	},
 }
 
 func TestParsePosition(t *testing.T) {
@@ -46,7 +80,7 @@ func TestParsePosition(t *testing.T) {
 		t.Run(testName, func(t *testing.T) {
 			pos, err := parsePosition(testCase.providedArg)
 			if err != nil {
-				if testCase.expectErr {
+				if !testCase.expectErr {
 					t.Errorf("Unexpected error: %s", err)
 				}
 				return
@@ -73,3 +107,52 @@ func TestParsePosition(t *testing.T) {
 		})
 	}
 }
+
+func TestEnvFlag(t *testing.T) {
+	var env envFlag
+	for _, arg := range []string{"A=1", "B=x=y", "C="} {
+		if err := env.Set(arg); err != nil {
+			t.Errorf("Unexpected error setting %s: %s", arg, err)
+		}
+	}
+	for _, arg := range []string{"A", "=1", ""} {
+		if err := env.Set(arg); err == nil {
+			t.Errorf("Unexpectedly no error setting '%s'", arg)
+		}
+	}
+	if env.String() != "A=1 B=x=y C=" {
+		t.Errorf("Unexpected env: %s", env.String())
+	}
+}
+
+func TestSerialFlag(t *testing.T) {
+	var serial serialFlag
+	for _, arg := range []string{"^TestDB", "Integration$"} {
+		if err := serial.Set(arg); err != nil {
+			t.Errorf("Unexpected error setting %s: %s", arg, err)
+		}
+	}
+	if err := serial.Set("("); err == nil {
+		t.Errorf("Unexpectedly no error setting invalid regular expression")
+	}
+	if serial.String() != "^TestDB Integration$" {
+		t.Errorf("Unexpected serial: %s", serial.String())
+	}
+}
+
+func TestGroupFlag(t *testing.T) {
+	var groups groupFlag
+	for _, arg := range []string{"db=^TestDB", "cache=Cache", "db=Postgres"} {
+		if err := groups.Set(arg); err != nil {
+			t.Errorf("Unexpected error setting %s: %s", arg, err)
+		}
+	}
+	for _, arg := range []string{"db", "=^TestDB", "db=("} {
+		if err := groups.Set(arg); err == nil {
+			t.Errorf("Unexpectedly no error setting '%s'", arg)
+		}
+	}
+	if groups.String() != "cache=Cache db=(?:^TestDB)|(?:Postgres)" {
+		t.Errorf("Unexpected groups: %s", groups.String())
+	}
+}
@@ -0,0 +1,224 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ShawnROGrady/go-find-tests/tester"
+)
+
+// command is a subcommand of the tool, invoked as '<tool> <name> [flags] [args]'
+type command struct {
+	usage       string // arguments following the command name
+	description string
+	// flags registers the flags of the command and returns the function which runs it with the remaining arguments
+	flags func(fs *flag.FlagSet) func(args []string, jsonFmt bool, dst io.Writer) error
+}
+
+// commands are keyed by name, if the first argument does not name a command the default covering test search is run
+var commands = map[string]command{
+	"html":             htmlCommand,
+	"index":            indexCommand,
+	"matrix":           matrixCommand,
+	"minimize":         minimizeCommand,
+	"query":            queryCommand,
+	"redundant":        redundantCommand,
+	"report-uncovered": reportUncoveredCommand,
+	"unstable":         unstableCommand,
+}
+
+// runCommand parses the flags of the command and runs it, returning the exit code
+func runCommand(name string, cmd command, args []string, dst io.Writer) int {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard) // errors are reported through printError
+	jsonFmt := fs.Bool("json", false, "Print the output in json format")
+	run := cmd.flags(fs)
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			printCommandHelp(os.Stdout, name, cmd, fs)
+			return exitOK
+		}
+		return printError(os.Stderr, &usageErr{msg: err.Error()}, *jsonFmt)
+	}
+	if err := applyConfig(fs, configTarget(fs.Args())); err != nil {
+		return printError(os.Stderr, err, *jsonFmt)
+	}
+	if err := run(fs.Args(), *jsonFmt, dst); err != nil {
+		return printError(os.Stderr, err, *jsonFmt)
+	}
+	return exitOK
+}
+
+func printCommandHelp(dst io.Writer, name string, cmd command, fs *flag.FlagSet) {
+	fmt.Fprintf(dst, "Usage: %s %s [flags] %s\n", os.Args[0], name, cmd.usage)
+	fmt.Fprintf(dst, "Description: %s\n", cmd.description)
+	fmt.Fprint(dst, "Optional flags:\n")
+	fs.VisitAll(func(f *flag.Flag) {
+		fmt.Fprintf(dst, "\t-%s: %s [default = %v]\n", f.Name, f.Usage, f.DefValue)
+	})
+}
+
+// commandNames returns the names of all commands in alphabetical order
+func commandNames() []string {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// testerFlags are the flags shared by commands which run the tests of a package
+type testerFlags struct {
+	short       *bool
+	run         *string
+	skip        *string
+	seq         *bool
+	timeout     *time.Duration
+	tags        *string
+	keep        *string
+	testExec    *string
+	isolate     *bool
+	copyPackage *bool
+	env         envFlag
+	serial      serialFlag
+	groups      groupFlag
+}
+
+func addTesterFlags(fs *flag.FlagSet) *testerFlags {
+	f := &testerFlags{
+		short:       fs.Bool("short", false, "Sets '-short' flag when running tests"),
+		run:         fs.String("run", ".", runUsage),
+		skip:        fs.String("skip", "", skipUsage),
+		seq:         fs.Bool("seq", false, "Run all tests sequentially. Greatly reduces performance but may be necessary for integration tests"),
+		timeout:     fs.Duration("timeout", 0, "Sets '-timeout' when running each test, if 0 the default of the testing package is used"),
+		tags:        fs.String("tags", "", tagsUsage),
+		keep:        fs.String("keep", "", keepUsage),
+		testExec:    fs.String("test-exec", "", testExecUsage),
+		isolate:     fs.Bool("isolate", false, isolateUsage),
+		copyPackage: fs.Bool("copy-pkg", false, copyPackageUsage),
+	}
+	fs.Var(&f.env, "env", envUsage)
+	fs.Var(&f.serial, "serial", serialUsage)
+	fs.Var(&f.groups, "group", groupUsage)
+	return f
+}
+
+func (f *testerFlags) config() tester.Config {
+	return tester.Config{
+		Short:       *f.short,
+		Run:         *f.run,
+		Skip:        *f.skip,
+		Seq:         *f.seq,
+		Timeout:     *f.timeout,
+		Tags:        *f.tags,
+		KeepDir:     *f.keep,
+		ExecWrapper: *f.testExec,
+		Isolate:     *f.isolate,
+		CopyPackage: *f.copyPackage,
+		Env:         f.env,
+		Serial:      f.serial,
+		Groups:      f.groups,
+	}
+}
+
+// usage of the test selection flags, shared with the default command
+const (
+	runUsage  = "Run only tests matching the regular expression, '/' separated levels select the subtests which are run, e.g. 'TestParse/^valid_'"
+	skipUsage = "Do not run tests matching the regular expression, which may have '/' separated levels as with -run"
+)
+
+// usage of the build flags, shared with the default command
+const (
+	tagsUsage     = "A comma separated list of build tags to set when listing and compiling tests"
+	testExecUsage = "Run each test binary using the program, as with 'go test -exec', e.g. 'sudo -E' or a sandbox script. Relative paths are relative to the package directory"
+	keepUsage     = "Keep the test binary, and the cover profile and output of each test, in the directory along with a '" + tester.ManifestFile + "' describing them"
+)
+
+// usage of the flags which isolate test processes, shared with the default command
+const (
+	isolateUsage     = "Run each test process with its own empty HOME, TMPDIR, and XDG directories so that tests which share them can run in parallel"
+	copyPackageUsage = "Run each test process in its own copy of the package directory, including testdata"
+	envUsage         = "Set an additional 'KEY=value' environment variable for each test process, may be repeated"
+)
+
+// usage of the flags which restrict which tests run concurrently, shared with the default command
+const (
+	serialUsage = "Never run top-level tests matching the regular expression concurrently with any other test, may be repeated"
+	groupUsage  = "With 'name=regexp': never run top-level tests matching the regular expression concurrently with each other, may be repeated"
+)
+
+// envFlag collects 'KEY=value' environment variables from a repeated flag
+type envFlag []string
+
+func (e *envFlag) String() string {
+	return strings.Join(*e, " ")
+}
+
+func (e *envFlag) Set(s string) error {
+	if i := strings.Index(s, "="); i <= 0 {
+		return fmt.Errorf("environment variable '%s' doesn't match format 'KEY=value'", s)
+	}
+	*e = append(*e, s)
+	return nil
+}
+
+// serialFlag collects the regular expressions of serial tests from a repeated flag
+type serialFlag []string
+
+func (s *serialFlag) String() string {
+	return strings.Join(*s, " ")
+}
+
+func (s *serialFlag) Set(pattern string) error {
+	if _, err := regexp.Compile(pattern); err != nil {
+		return err
+	}
+	*s = append(*s, pattern)
+	return nil
+}
+
+// groupFlag collects 'name=regexp' groups of mutually exclusive tests from a repeated flag
+// repeating a name adds an alternative to the group's regular expression
+type groupFlag map[string]string
+
+func (g *groupFlag) String() string {
+	names := make([]string, 0, len(*g))
+	for name := range *g {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	groups := make([]string, len(names))
+	for i, name := range names {
+		groups[i] = name + "=" + (*g)[name]
+	}
+	return strings.Join(groups, " ")
+}
+
+func (g *groupFlag) Set(s string) error {
+	i := strings.Index(s, "=")
+	if i <= 0 {
+		return fmt.Errorf("group '%s' doesn't match format 'name=regexp'", s)
+	}
+	name, pattern := s[:i], s[i+1:]
+	if _, err := regexp.Compile(pattern); err != nil {
+		return err
+	}
+	if *g == nil {
+		*g = make(groupFlag)
+	}
+	if prev, ok := (*g)[name]; ok {
+		pattern = "(?:" + prev + ")|(?:" + pattern + ")"
+	}
+	(*g)[name] = pattern
+	return nil
+}
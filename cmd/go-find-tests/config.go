@@ -0,0 +1,263 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ShawnROGrady/go-find-tests/tester"
+)
+
+// configFile is the name of the project configuration file in the root of the module
+const configFile = ".go-find-tests.json"
+
+// projectConfig provides the defaults of flags which aren't set on the command line
+type projectConfig struct {
+	options
+	// Packages override the options for the package directories matching each key,
+	// which is relative to the module root and may end in '/...' to also match subdirectories
+	Packages map[string]options `json:"packages"`
+}
+
+// options are the values of flags, nil fields leave the flag's own default
+type options struct {
+	Tags        *string           `json:"tags"`
+	Short       *bool             `json:"short"`
+	Run         *string           `json:"run"`
+	Skip        *string           `json:"skip"`
+	Seq         *bool             `json:"seq"`
+	Serial      []string          `json:"serial"`
+	Groups      map[string]string `json:"groups"`
+	Timeout     *string           `json:"timeout"`
+	TestExec    *string           `json:"test_exec"`
+	Env         map[string]string `json:"env"`
+	Isolate     *bool             `json:"isolate"`
+	CopyPackage *bool             `json:"copy_pkg"`
+	Index       *string           `json:"index"` // relative to the module root, not overridable per package
+	JSON        *bool             `json:"json"`
+	LineFmt     *string           `json:"line_fmt"`
+	Sort        *string           `json:"sort"`
+}
+
+// readProjectConfig reads the configuration file in the root of the module, returning nil if there is none
+func readProjectConfig(root string) (*projectConfig, error) {
+	path := filepath.Join(root, configFile)
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var conf projectConfig
+	dec := json.NewDecoder(f)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&conf); err != nil {
+		return nil, &usageErr{msg: fmt.Sprintf("Error parsing %s: %s", path, err)}
+	}
+	return &conf, nil
+}
+
+// forDir returns the options of the package in dir, applying the matching package overrides from least to most specific
+func (c *projectConfig) forDir(root, dir string) options {
+	opts := c.options
+	rel, err := filepath.Rel(root, dir)
+	if err != nil {
+		return opts
+	}
+	rel = filepath.ToSlash(rel)
+
+	var matching []string
+	for key := range c.Packages {
+		if packageMatches(key, rel) {
+			matching = append(matching, key)
+		}
+	}
+	sort.Slice(matching, func(i, j int) bool {
+		pi, pj := packagePrefix(matching[i]), packagePrefix(matching[j])
+		if len(pi) != len(pj) {
+			return len(pi) < len(pj)
+		}
+		// an exact match is more specific than a wildcard with the same prefix
+		return strings.HasSuffix(matching[i], "...") && !strings.HasSuffix(matching[j], "...")
+	})
+	for _, key := range matching {
+		opts = opts.merge(c.Packages[key])
+	}
+	return opts
+}
+
+// packagePrefix returns the directory of a package key without any leading './' or trailing '/...'
+func packagePrefix(key string) string {
+	key = strings.TrimSuffix(strings.TrimSuffix(key, "..."), "/")
+	key = strings.TrimPrefix(key, "./")
+	if key == "" {
+		return "."
+	}
+	return key
+}
+
+// packageMatches returns whether the package key matches the slash separated directory relative to the module root
+func packageMatches(key, rel string) bool {
+	prefix := packagePrefix(key)
+	if !strings.HasSuffix(key, "...") {
+		return prefix == rel
+	}
+	return prefix == "." || rel == prefix || strings.HasPrefix(rel, prefix+"/")
+}
+
+// merge returns the options with every field set in override replaced
+func (o options) merge(override options) options {
+	if override.Tags != nil {
+		o.Tags = override.Tags
+	}
+	if override.Short != nil {
+		o.Short = override.Short
+	}
+	if override.Run != nil {
+		o.Run = override.Run
+	}
+	if override.Skip != nil {
+		o.Skip = override.Skip
+	}
+	if override.Seq != nil {
+		o.Seq = override.Seq
+	}
+	if override.Serial != nil {
+		o.Serial = override.Serial
+	}
+	if override.Groups != nil {
+		o.Groups = override.Groups
+	}
+	if override.Timeout != nil {
+		o.Timeout = override.Timeout
+	}
+	if override.TestExec != nil {
+		o.TestExec = override.TestExec
+	}
+	if override.Env != nil {
+		o.Env = override.Env
+	}
+	if override.Isolate != nil {
+		o.Isolate = override.Isolate
+	}
+	if override.CopyPackage != nil {
+		o.CopyPackage = override.CopyPackage
+	}
+	if override.JSON != nil {
+		o.JSON = override.JSON
+	}
+	if override.LineFmt != nil {
+		o.LineFmt = override.LineFmt
+	}
+	if override.Sort != nil {
+		o.Sort = override.Sort
+	}
+	return o
+}
+
+type flagValue struct {
+	name, value string
+}
+
+// flagValues returns the options as the flags they set, repeated flags are in a stable order
+func (o options) flagValues() []flagValue {
+	var values []flagValue
+	addString := func(name string, v *string) {
+		if v != nil {
+			values = append(values, flagValue{name: name, value: *v})
+		}
+	}
+	addBool := func(name string, v *bool) {
+		if v != nil {
+			values = append(values, flagValue{name: name, value: strconv.FormatBool(*v)})
+		}
+	}
+	addMap := func(name string, m map[string]string) {
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			values = append(values, flagValue{name: name, value: k + "=" + m[k]})
+		}
+	}
+
+	addString("tags", o.Tags)
+	addBool("short", o.Short)
+	addString("run", o.Run)
+	addString("skip", o.Skip)
+	addBool("seq", o.Seq)
+	for _, pattern := range o.Serial {
+		values = append(values, flagValue{name: "serial", value: pattern})
+	}
+	addMap("group", o.Groups)
+	addString("timeout", o.Timeout)
+	addString("test-exec", o.TestExec)
+	addMap("env", o.Env)
+	addBool("isolate", o.Isolate)
+	addBool("copy-pkg", o.CopyPackage)
+	addBool("json", o.JSON)
+	addString("line-fmt", o.LineFmt)
+	addString("sort", o.Sort)
+	return values
+}
+
+// configTarget returns the path whose package selects the overrides of the configuration file,
+// i.e. the file or directory of the first argument if it exists, otherwise the working directory
+func configTarget(args []string) string {
+	if len(args) == 0 {
+		return "."
+	}
+	path := args[0]
+	if _, err := os.Stat(path); err != nil {
+		// may be a position
+		path = strings.SplitN(path, ":", 2)[0]
+	}
+	info, err := os.Stat(path)
+	switch {
+	case err != nil:
+		return "."
+	case info.IsDir():
+		return path
+	default:
+		return filepath.Dir(path)
+	}
+}
+
+// applyConfig sets each flag which wasn't set on the command line to its value in the configuration file of the module which owns dir
+// options for flags the command doesn't have are ignored
+func applyConfig(fs *flag.FlagSet, dir string) error {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	root := tester.ModuleRoot(abs)
+	if root == "" {
+		return nil
+	}
+	conf, err := readProjectConfig(root)
+	if err != nil || conf == nil {
+		return err
+	}
+
+	explicit := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { explicit[f.Name] = true })
+	for _, v := range conf.forDir(root, abs).flagValues() {
+		if explicit[v.name] || fs.Lookup(v.name) == nil {
+			continue
+		}
+		if err := fs.Set(v.name, v.value); err != nil {
+			return &usageErr{msg: fmt.Sprintf("Invalid '%s' in %s: %s", v.name, filepath.Join(root, configFile), err)}
+		}
+	}
+	return nil
+}
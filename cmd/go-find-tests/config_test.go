@@ -0,0 +1,165 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const testProjectConfig = `{
+	"short": true,
+	"tags": "integration",
+	"timeout": "1m",
+	"env": {"B": "2", "A": "1"},
+	"index": ".cache/index.json",
+	"packages": {
+		"db/...": {"serial": ["^TestMigrate$"], "timeout": "5m", "test_exec": "./netns.sh db"},
+		"db/postgres": {"groups": {"pg": "^TestPG"}, "short": false, "skip": "^TestPGSlow$"},
+		"./...": {"run": "^Test"}
+	}
+}`
+
+// writeTestModule creates a module with the provided configuration file, returning its root and a function which removes it
+func writeTestModule(t *testing.T, config string) (string, func()) {
+	t.Helper()
+	root, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	files := map[string]string{
+		"go.mod":                       "module example.com/config\n",
+		"db/postgres/postgres_test.go": "package postgres\n",
+	}
+	if config != "" {
+		files[configFile] = config
+	}
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	}
+	return root, func() { os.RemoveAll(root) }
+}
+
+var forDirTests = map[string]struct {
+	dir            string
+	expectedValues string
+}{
+	"module_root": {
+		dir:            ".",
+		expectedValues: "[{tags integration} {short true} {run ^Test} {timeout 1m} {env A=1} {env B=2}]",
+	},
+	"wildcard_override": {
+		dir:            "db",
+		expectedValues: "[{tags integration} {short true} {run ^Test} {serial ^TestMigrate$} {timeout 5m} {test-exec ./netns.sh db} {env A=1} {env B=2}]",
+	},
+	"most_specific_override_last": {
+		dir:            "db/postgres",
+		expectedValues: "[{tags integration} {short false} {run ^Test} {skip ^TestPGSlow$} {serial ^TestMigrate$} {group pg=^TestPG} {timeout 5m} {test-exec ./netns.sh db} {env A=1} {env B=2}]",
+	},
+	"prefix_is_not_parent": {
+		dir:            "dbx",
+		expectedValues: "[{tags integration} {short true} {run ^Test} {timeout 1m} {env A=1} {env B=2}]",
+	},
+}
+
+func TestProjectConfigForDir(t *testing.T) {
+	root, cleanup := writeTestModule(t, testProjectConfig)
+	defer cleanup()
+	conf, err := readProjectConfig(root)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	for testName, test := range forDirTests {
+		values := conf.forDir(root, filepath.Join(root, test.dir)).flagValues()
+		if fmt.Sprint(values) != test.expectedValues {
+			t.Errorf("Unexpected flag values for %s (expected = %s, actual = %v)", testName, test.expectedValues, values)
+		}
+	}
+}
+
+func TestReadProjectConfig(t *testing.T) {
+	root, cleanup := writeTestModule(t, "")
+	defer cleanup()
+	if conf, err := readProjectConfig(root); conf != nil || err != nil {
+		t.Errorf("Unexpected result without config file (conf = %v, err = %v)", conf, err)
+	}
+
+	if err := ioutil.WriteFile(filepath.Join(root, configFile), []byte(`{"shrot": true}`), 0644); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if _, err := readProjectConfig(root); err == nil {
+		t.Errorf("Unexpectedly no error for unknown option")
+	}
+}
+
+func TestApplyConfig(t *testing.T) {
+	root, cleanup := writeTestModule(t, testProjectConfig)
+	defer cleanup()
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	testerFlags := addTesterFlags(fs)
+	if err := fs.Parse([]string{"-timeout", "10s", "-env", "C=3"}); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if err := applyConfig(fs, filepath.Join(root, "db", "postgres")); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	conf := testerFlags.config()
+	if conf.Timeout != 10*time.Second {
+		t.Errorf("Unexpected timeout, flag should take precedence (expected = 10s, actual = %s)", conf.Timeout)
+	}
+	if fmt.Sprint(conf.Env) != "[C=3]" {
+		t.Errorf("Unexpected env, flag should replace the configured env (expected = [C=3], actual = %v)", conf.Env)
+	}
+	if conf.Short || conf.Tags != "integration" || conf.Run != "^Test" {
+		t.Errorf("Unexpected config from file (short = %t, tags = %s, run = %s)", conf.Short, conf.Tags, conf.Run)
+	}
+	if fmt.Sprint(conf.Serial, conf.Groups) != "[^TestMigrate$] map[pg:^TestPG]" {
+		t.Errorf("Unexpected policy (serial = %v, groups = %v)", conf.Serial, conf.Groups)
+	}
+
+	path, err := indexPath("", root)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if expected := filepath.Join(root, ".cache", "index.json"); path != expected {
+		t.Errorf("Unexpected index path (expected = %s, actual = %s)", expected, path)
+	}
+}
+
+func TestApplyConfigInvalidValue(t *testing.T) {
+	root, cleanup := writeTestModule(t, `{"timeout": "soon"}`)
+	defer cleanup()
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	addTesterFlags(fs)
+	if err := applyConfig(fs, root); err == nil {
+		t.Errorf("Unexpectedly no error for invalid timeout")
+	}
+}
+
+func TestConfigTarget(t *testing.T) {
+	tests := map[string][]string{
+		".":           {"example.com/missing"},
+		"../../cover": {"../../cover/profile.go:10.2"},
+		"../../index": {"../../index"},
+	}
+	for expected, args := range tests {
+		if actual := configTarget(args); actual != expected {
+			t.Errorf("Unexpected target of %v (expected = %s, actual = %s)", args, expected, actual)
+		}
+	}
+	if actual := configTarget(nil); actual != "." {
+		t.Errorf("Unexpected target without args (expected = ., actual = %s)", actual)
+	}
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// diffLines are the lines added by a unified diff, keyed by the slash separated path of the new file
+type diffLines map[string]map[int]bool
+
+// parseDiff parses a unified diff, e.g. the output of 'git diff', returning the lines it adds to each file
+// paths have any 'b/' prefix added by git removed, deleted files have no added lines
+func parseDiff(r io.Reader) (diffLines, error) {
+	var (
+		lines            = make(diffLines)
+		scanner          = bufio.NewScanner(r)
+		file             string
+		line             int // number of the next line of the new file within a hunk
+		oldLeft, newLeft int // lines remaining in the current hunk
+		lineNum          int
+	)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		lineNum++
+		text := scanner.Text()
+		if oldLeft > 0 || newLeft > 0 {
+			switch {
+			case strings.HasPrefix(text, "+"):
+				if file != "" {
+					lines[file][line] = true
+				}
+				line++
+				newLeft--
+			case strings.HasPrefix(text, "-"):
+				oldLeft--
+			case strings.HasPrefix(text, `\`):
+				// '\ No newline at end of file'
+			default:
+				// context, an empty line is context whose leading space was trimmed
+				line++
+				oldLeft--
+				newLeft--
+			}
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(text, "+++ "):
+			name := strings.TrimPrefix(text, "+++ ")
+			if tab := strings.IndexByte(name, '\t'); tab != -1 {
+				// timestamp of 'diff -u'
+				name = name[:tab]
+			}
+			file = ""
+			if name != "/dev/null" {
+				file = path.Clean(strings.TrimPrefix(name, "b/"))
+				if lines[file] == nil {
+					lines[file] = make(map[int]bool)
+				}
+			}
+		case strings.HasPrefix(text, "@@ "):
+			var err error
+			if line, oldLeft, newLeft, err = parseHunkHeader(text); err != nil {
+				return nil, fmt.Errorf("line %d: malformed hunk header '%s'", lineNum, text)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
+// parseHunkHeader parses a header of the form '@@ -l,s +l,s @@', returning the first line of the new file
+// and the number of lines of the old and new file in the hunk
+func parseHunkHeader(text string) (int, int, int, error) {
+	fields := strings.Fields(text)
+	if len(fields) < 4 || fields[3] != "@@" || !strings.HasPrefix(fields[1], "-") || !strings.HasPrefix(fields[2], "+") {
+		return 0, 0, 0, fmt.Errorf("expected '@@ -l,s +l,s @@'")
+	}
+	_, oldCount, err := parseHunkRange(fields[1][1:])
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	start, newCount, err := parseHunkRange(fields[2][1:])
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	return start, oldCount, newCount, nil
+}
+
+// parseHunkRange parses 'l,s' or 'l', in which case the number of lines is 1
+func parseHunkRange(s string) (int, int, error) {
+	parts := strings.SplitN(s, ",", 2)
+	start, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(parts) == 1 {
+		return start, 1, nil
+	}
+	count, err := strconv.Atoi(parts[1])
+	return start, count, err
+}
+
+// overlaps returns whether the diff adds any line of the file within the inclusive range
+func (d diffLines) overlaps(file string, start, end int) bool {
+	added := d[file]
+	for line := start; line <= end; line++ {
+		if added[line] {
+			return true
+		}
+	}
+	return false
+}
+
+// packageDirs returns the slash separated directories of the packages whose non-test go files the diff adds lines to
+func (d diffLines) packageDirs() []string {
+	seen := make(map[string]bool)
+	dirs := []string{}
+	for file, added := range d {
+		if len(added) == 0 || !strings.HasSuffix(file, ".go") || strings.HasSuffix(file, "_test.go") || inTestdata(file) {
+			continue
+		}
+		dir := path.Dir(file)
+		if !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+	sort.Strings(dirs)
+	return dirs
+}
+
+// inTestdata returns whether the slash separated path is within a testdata directory, which the go command ignores
+func inTestdata(file string) bool {
+	for _, elem := range strings.Split(path.Dir(file), "/") {
+		if elem == "testdata" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+)
+
+const testDiff = `diff --git a/pkg/a.go b/pkg/a.go
+index 1111111..2222222 100644
+--- a/pkg/a.go
++++ b/pkg/a.go
+@@ -1,4 +1,5 @@
+ package pkg
+-
++// added
++
+ func A() {}
+
+@@ -10,3 +11,3 @@ func B() {
+ 	b := 1
+-	return b
++	return b + 1
+ }
+diff --git a/pkg/a_test.go b/pkg/a_test.go
+--- a/pkg/a_test.go
++++ b/pkg/a_test.go
+@@ -1 +1,2 @@
+ package pkg
++// test
+diff --git a/old.go b/old.go
+deleted file mode 100644
+--- a/old.go
++++ /dev/null
+@@ -1,2 +0,0 @@
+-package old
+-// removed
+diff --git a/pkg/sub/testdata/x.go b/pkg/sub/testdata/x.go
+new file mode 100644
+--- /dev/null
++++ b/pkg/sub/testdata/x.go
+@@ -0,0 +1 @@
++package x
+diff --git a/pkg/sub/sub.go b/pkg/sub/sub.go
+new file mode 100644
+--- /dev/null
++++ b/pkg/sub/sub.go
+@@ -0,0 +1,2 @@
++package sub
++
+`
+
+func TestParseDiff(t *testing.T) {
+	added, err := parseDiff(strings.NewReader(testDiff))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	actual := []string{}
+	for file, lines := range added {
+		numbers := []int{}
+		for line := range lines {
+			numbers = append(numbers, line)
+		}
+		sort.Ints(numbers)
+		actual = append(actual, fmt.Sprintf("%s:%v", file, numbers))
+	}
+	sort.Strings(actual)
+	expected := "[pkg/a.go:[2 3 12] pkg/a_test.go:[2] pkg/sub/sub.go:[1 2] pkg/sub/testdata/x.go:[1]]"
+	if fmt.Sprint(actual) != expected {
+		t.Errorf("Unexpected added lines (expected = %s, actual = %v)", expected, actual)
+	}
+
+	if dirs := added.packageDirs(); fmt.Sprint(dirs) != "[pkg pkg/sub]" {
+		t.Errorf("Unexpected package directories (expected = [pkg pkg/sub], actual = %v)", dirs)
+	}
+	if !added.overlaps("pkg/a.go", 10, 12) || added.overlaps("pkg/a.go", 4, 11) {
+		t.Error("Unexpected overlap of pkg/a.go")
+	}
+}
+
+func TestParseDiffMalformedHunk(t *testing.T) {
+	_, err := parseDiff(strings.NewReader("+++ b/a.go\n@@ -1 +x @@\n"))
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
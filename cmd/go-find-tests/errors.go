@@ -0,0 +1,143 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ShawnROGrady/go-find-tests/index"
+	"github.com/ShawnROGrady/go-find-tests/tester"
+)
+
+// exit codes, documented in the README
+const (
+	exitOK              = 0
+	exitError           = 1 // any error not covered by a more specific code
+	exitUsage           = 2 // invalid flags or arguments
+	exitPackageNotFound = 3
+	exitCompile         = 4
+	exitTestFailed      = 5
+	exitTimeout         = 6
+	exitProfile         = 7
+	exitNotFound        = 8 // a queried symbol or test is not in the index
+)
+
+// errorKinds maps the sentinel errors of the tester to the kind and exit code reported to the user
+var errorKinds = []struct {
+	err  error
+	kind string
+	code int
+}{
+	{err: tester.ErrPackageNotFound, kind: "package_not_found", code: exitPackageNotFound},
+	{err: tester.ErrCompile, kind: "compile", code: exitCompile},
+	{err: tester.ErrTimeout, kind: "timeout", code: exitTimeout},
+	{err: tester.ErrTestFailed, kind: "test_failed", code: exitTestFailed},
+	{err: tester.ErrProfile, kind: "profile", code: exitProfile},
+	{err: index.ErrNotFound, kind: "not_found", code: exitNotFound},
+	{err: errUsage, kind: "usage", code: exitUsage},
+}
+
+var errUsage = errors.New("usage error")
+
+// usageErr represents an invalid invocation of the tool
+type usageErr struct {
+	msg string
+}
+
+func (u *usageErr) Error() string { return u.msg }
+
+func (u *usageErr) Is(target error) bool { return target == errUsage }
+
+// errorKind returns the kind and exit code associated with err
+func errorKind(err error) (string, int) {
+	for _, k := range errorKinds {
+		if errors.Is(err, k.err) {
+			return k.kind, k.code
+		}
+	}
+	return "error", exitError
+}
+
+// errorOutput is the json representation of an error
+type errorOutput struct {
+	Kind     string `json:"kind"`
+	ExitCode int    `json:"exit_code"`
+	Message  string `json:"message"`
+	Package  string `json:"package,omitempty"`
+	Path     string `json:"path,omitempty"`
+	Test     string `json:"test,omitempty"`
+	Output   string `json:"output,omitempty"`
+	Command  string `json:"command,omitempty"`
+	Stderr   string `json:"stderr,omitempty"`
+}
+
+func newErrorOutput(err error) errorOutput {
+	kind, code := errorKind(err)
+	out := errorOutput{
+		Kind:     kind,
+		ExitCode: code,
+		Message:  err.Error(),
+	}
+
+	var (
+		pkgErr     *tester.PackageNotFoundError
+		compileErr *tester.CompileError
+		testErr    *tester.TestError
+		timeoutErr *tester.TimeoutError
+		profErr    *tester.ProfileError
+		cmdErr     *tester.CommandError
+	)
+	if errors.As(err, &pkgErr) {
+		out.Path = pkgErr.Path
+	}
+	if errors.As(err, &compileErr) {
+		out.Package = compileErr.Package
+	}
+	if errors.As(err, &testErr) {
+		out.Test = testErr.Test
+		out.Output = strings.TrimSpace(testErr.Output)
+	}
+	if errors.As(err, &timeoutErr) {
+		out.Test = timeoutErr.Test
+	}
+	if errors.As(err, &profErr) {
+		out.Test = profErr.Test
+		out.Path = profErr.Path
+	}
+	if errors.As(err, &cmdErr) {
+		out.Command = strings.Join(cmdErr.Args, " ")
+		out.Stderr = strings.TrimSpace(cmdErr.Stderr)
+	}
+	return out
+}
+
+// printError writes err to dst and returns the exit code associated with it
+func printError(dst io.Writer, err error, jsonFmt bool) int {
+	out := newErrorOutput(err)
+	if !jsonFmt {
+		fmt.Fprintln(dst, err)
+		return out.ExitCode
+	}
+
+	b, marshalErr := json.Marshal(struct {
+		Error errorOutput `json:"error"`
+	}{Error: out})
+	if marshalErr != nil {
+		fmt.Fprintln(dst, err)
+		return out.ExitCode
+	}
+	fmt.Fprintf(dst, "%s\n", b)
+	return out.ExitCode
+}
+
+// exitCode returns the exit code of a failed run, writing err to dst unless it is the failure of the tests run with -exec
+// whose output has already been streamed, in which case the exit status of 'go test' is returned
+func exitCode(dst io.Writer, err error, jsonFmt bool) int {
+	var execErr *tester.ExecError
+	if errors.As(err, &execErr) && execErr.ExitCode() > 0 {
+		return execErr.ExitCode()
+	}
+	return printError(dst, err, jsonFmt)
+}
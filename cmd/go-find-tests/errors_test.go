@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/ShawnROGrady/go-find-tests/tester"
+)
+
+var printErrorTests = map[string]struct {
+	err            error
+	jsonFmt        bool
+	expectedCode   int
+	expectedOutput string
+}{
+	"unknown_error": {
+		err:            errors.New("something went wrong"),
+		expectedCode:   exitError,
+		expectedOutput: "something went wrong\n",
+	},
+	"usage_error": {
+		err:            &usageErr{msg: "Position argument (fmt = 'file:line[.col]') required"},
+		expectedCode:   exitUsage,
+		expectedOutput: "Position argument (fmt = 'file:line[.col]') required\n",
+	},
+	"wrapped_test_error_json": {
+		err: fmt.Errorf("Error determining covering tests: %w", fmt.Errorf("error running test 'TestSum': %w", &tester.TestError{
+			Test:   "TestSum",
+			Output: "fail_test.go:12: Unexpected sum\n",
+			Err:    &tester.CommandError{Args: []string{"go", "tool", "test2json"}, Err: &exec.ExitError{}},
+		})),
+		jsonFmt:        true,
+		expectedCode:   exitTestFailed,
+		expectedOutput: `{"error":{"kind":"test_failed","exit_code":5,"message":"Error determining covering tests: error running test 'TestSum': FAIL: TestSum - fail_test.go:12: Unexpected sum","test":"TestSum","output":"fail_test.go:12: Unexpected sum","command":"go tool test2json"}}` + "\n",
+	},
+	"compile_error_json": {
+		err: &tester.CompileError{
+			Package: "github.com/org/repo/pkg",
+			Err:     &tester.CommandError{Args: []string{"go", "test", "-c"}, Stderr: "syntax error\n", Err: errors.New("exit status 1")},
+		},
+		jsonFmt:        true,
+		expectedCode:   exitCompile,
+		expectedOutput: `{"error":{"kind":"compile","exit_code":4,"message":"error compiling test for go pkg github.com/org/repo/pkg: exit status 1 - syntax error\n","package":"github.com/org/repo/pkg","command":"go test -c","stderr":"syntax error"}}` + "\n",
+	},
+	"package_not_found": {
+		err:            &tester.PackageNotFoundError{Path: "./bad/file.go", Err: errors.New("exit status 1")},
+		expectedCode:   exitPackageNotFound,
+		expectedOutput: "error finding go pkg from './bad/file.go': exit status 1\n",
+	},
+	"timeout": {
+		err:            &tester.TimeoutError{Test: "TestSlow", Timeout: 2 * time.Second, Err: errors.New("exit status 1")},
+		expectedCode:   exitTimeout,
+		expectedOutput: "TIMEOUT: TestSlow - exceeded 2s\n",
+	},
+	"default_timeout": {
+		err:            &tester.TimeoutError{Test: "TestSlow", Err: errors.New("exit status 1")},
+		expectedCode:   exitTimeout,
+		expectedOutput: "TIMEOUT: TestSlow - exceeded the default timeout\n",
+	},
+	"profile_error": {
+		err:            &tester.ProfileError{Test: "TestA", Err: errors.New("Unmatched line: bad")},
+		expectedCode:   exitProfile,
+		expectedOutput: "error parsing coverage output of 'TestA': Unmatched line: bad\n",
+	},
+}
+
+func TestPrintError(t *testing.T) {
+	for testName, testCase := range printErrorTests {
+		t.Run(testName, func(t *testing.T) {
+			var b bytes.Buffer
+
+			code := printError(&b, testCase.err, testCase.jsonFmt)
+			if code != testCase.expectedCode {
+				t.Errorf("Unexpected exit code (expected = %d, actual = %d)", testCase.expectedCode, code)
+			}
+
+			if actual := b.String(); actual != testCase.expectedOutput {
+				t.Errorf("Unexpected output (expected = '%s', actual = '%s')", testCase.expectedOutput, actual)
+			}
+		})
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	testErr := exec.Command("sh", "-c", "exit 3").Run()
+	if testErr == nil {
+		t.Fatal("Expected command to fail")
+	}
+
+	tests := map[string]struct {
+		err            error
+		expectedCode   int
+		expectedOutput string
+	}{
+		"exec_error": {
+			err:          fmt.Errorf("Error running covering tests: %w", &tester.ExecError{Package: "github.com/org/repo/pkg", Err: &tester.CommandError{Args: []string{"go", "test"}, Err: testErr}}),
+			expectedCode: 3,
+		},
+		"compile_error": {
+			err:            &tester.CompileError{Package: "github.com/org/repo/pkg", Err: &tester.CommandError{Args: []string{"go", "test", "-c"}, Err: testErr}},
+			expectedCode:   exitCompile,
+			expectedOutput: "error compiling test for go pkg github.com/org/repo/pkg: exit status 3\n",
+		},
+	}
+
+	for testName, testCase := range tests {
+		t.Run(testName, func(t *testing.T) {
+			var b bytes.Buffer
+
+			code := exitCode(&b, testCase.err, false)
+			if code != testCase.expectedCode {
+				t.Errorf("Unexpected exit code (expected = %d, actual = %d)", testCase.expectedCode, code)
+			}
+
+			if actual := b.String(); actual != testCase.expectedOutput {
+				t.Errorf("Unexpected output (expected = '%s', actual = '%s')", testCase.expectedOutput, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,281 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/ShawnROGrady/go-find-tests/finder"
+	"github.com/ShawnROGrady/go-find-tests/tester"
+)
+
+var htmlCommand = command{
+	usage:       "package",
+	description: "runs every test of the package once and writes a self-contained html report of the source annotated with the tests which cover each line",
+	flags: func(fs *flag.FlagSet) func(args []string, jsonFmt bool, dst io.Writer) error {
+		var (
+			testerFlags = addTesterFlags(fs)
+			out         = fs.String("o", "", "File to write the report to, if empty the report is written to stdout")
+		)
+		return func(args []string, jsonFmt bool, dst io.Writer) error {
+			if len(args) != 1 {
+				return &usageErr{msg: "Package argument required"}
+			}
+
+			t, err := tester.NewPackage(args[0], testerFlags.config())
+			if err != nil {
+				return fmt.Errorf("Error constructing tester: %w", err)
+			}
+			profiles, err := t.Profiles()
+			if err != nil {
+				return fmt.Errorf("Error collecting test profiles: %w", err)
+			}
+			positions, err := finder.PackageTests(t.Dir())
+			if err != nil {
+				return fmt.Errorf("Error finding tests in %s: %w", t.Dir(), err)
+			}
+
+			m := tester.NewMatrix(profiles)
+			m.Package = t.Package()
+			report, err := newHTMLReport(m, t.Dir(), positions)
+			if err != nil {
+				return fmt.Errorf("Error reading package source: %w", err)
+			}
+
+			if *out != "" {
+				return writeHTMLFile(*out, report)
+			}
+			if err := htmlTemplate.Execute(dst, report); err != nil {
+				return fmt.Errorf("Error writing output: %w", err)
+			}
+			return nil
+		}
+	},
+}
+
+// writeHTMLFile writes the report to the file, the error of closing it is returned since it may fail to write the rest
+func writeHTMLFile(path string, report htmlReport) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := htmlTemplate.Execute(f, report); err != nil {
+		f.Close()
+		return fmt.Errorf("Error writing output: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("Error writing output: %w", err)
+	}
+	return nil
+}
+
+type htmlReport struct {
+	Package string
+	Tests   []htmlTest
+	Files   []htmlFile
+}
+
+type htmlTest struct {
+	Name string `json:"name"`
+	File string `json:"file,omitempty"`
+	Line int    `json:"line,omitempty"`
+}
+
+type htmlFile struct {
+	Name  string
+	Lines []htmlLine
+}
+
+type htmlLine struct {
+	Number  int
+	Text    string
+	Tests   []int // indexes of the tests which executed the line
+	InBlock bool  // whether the line contains statements
+}
+
+// Class returns the css class of the line
+func (l htmlLine) Class() string {
+	switch {
+	case len(l.Tests) != 0:
+		return "covered"
+	case l.InBlock:
+		return "uncovered"
+	}
+	return ""
+}
+
+// newHTMLReport annotates each line of the source files of the matrix in dir with the tests which covered it
+// files outside of dir, such as those of other packages, are not included
+func newHTMLReport(m tester.Matrix, dir string, positions map[string]finder.TestPosition) (htmlReport, error) {
+	report := htmlReport{
+		Package: m.Package,
+		Tests:   make([]htmlTest, len(m.Tests)),
+	}
+	for i, name := range m.Tests {
+		report.Tests[i].Name = name
+		if pos, ok := positions[name]; ok {
+			report.Tests[i].File = filepath.Base(pos.File)
+			report.Tests[i].Line = pos.Line
+		}
+	}
+
+	files := []string{}
+	fileLines := make(map[string][]htmlLine)
+	for j, b := range m.Blocks {
+		lines, ok := fileLines[b.File]
+		if !ok {
+			var err error
+			lines, err = readLines(filepath.Join(dir, b.File))
+			if err != nil && !os.IsNotExist(err) {
+				return htmlReport{}, err
+			}
+			fileLines[b.File] = lines
+			if lines != nil {
+				files = append(files, b.File)
+			}
+		}
+		if lines == nil {
+			continue
+		}
+
+		for n := b.StartLine; n <= b.EndLine && n <= len(lines); n++ {
+			line := &lines[n-1]
+			line.InBlock = true
+			for i := range m.Tests {
+				if m.Counts[i][j] != 0 && !containsIndex(line.Tests, i) {
+					line.Tests = append(line.Tests, i)
+				}
+			}
+		}
+	}
+
+	sort.Strings(files)
+	for _, file := range files {
+		lines := fileLines[file]
+		for i := range lines {
+			sort.Ints(lines[i].Tests)
+		}
+		report.Files = append(report.Files, htmlFile{Name: file, Lines: lines})
+	}
+	return report, nil
+}
+
+func readLines(path string) ([]htmlLine, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var (
+		lines   = []htmlLine{}
+		scanner = bufio.NewScanner(f)
+	)
+	for scanner.Scan() {
+		lines = append(lines, htmlLine{Number: len(lines) + 1, Text: scanner.Text()})
+	}
+	return lines, scanner.Err()
+}
+
+func containsIndex(indexes []int, i int) bool {
+	for _, index := range indexes {
+		if index == i {
+			return true
+		}
+	}
+	return false
+}
+
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Package}} - go-find-tests</title>
+<style>
+body { margin: 0; font-family: sans-serif; display: flex; height: 100vh; }
+#tests { width: 20em; overflow-y: auto; border-right: 1px solid #ccc; padding: 0.5em; }
+#tests li { cursor: pointer; list-style: none; padding: 0.1em 0.3em; }
+#tests li.selected { background: #ffe08a; }
+#tests .pos { color: #888; font-size: 0.8em; }
+#source { flex: 1; overflow: auto; }
+#details { position: fixed; right: 1em; bottom: 1em; max-width: 30em; background: #fff; border: 1px solid #ccc; padding: 0.5em; display: none; }
+h2 { font-size: 1em; background: #eee; margin: 0; padding: 0.3em; position: sticky; top: 0; }
+pre { margin: 0; }
+.line { display: block; cursor: pointer; }
+.line .num { display: inline-block; width: 4em; color: #888; text-align: right; padding-right: 1em; user-select: none; }
+.line .count { display: inline-block; width: 3em; color: #888; text-align: right; padding-right: 1em; }
+.covered { background: #e6ffed; }
+.uncovered { background: #ffeef0; }
+.highlighted { background: #ffe08a; }
+</style>
+</head>
+<body>
+<div id="tests">
+<h2>{{.Package}}</h2>
+<ul>
+{{- range $i, $test := .Tests}}
+<li data-test="{{$i}}">{{$test.Name}}{{if $test.File}} <span class="pos">{{$test.File}}:{{$test.Line}}</span>{{end}}</li>
+{{- end}}
+</ul>
+</div>
+<div id="source">
+{{- range .Files}}
+<h2>{{.Name}}</h2>
+<pre>
+{{- range .Lines}}
+<span class="line {{.Class}}" data-tests="{{range $i, $t := .Tests}}{{if $i}} {{end}}{{$t}}{{end}}"><span class="num">{{.Number}}</span><span class="count">{{if .Tests}}{{len .Tests}}{{end}}</span>{{.Text}}</span>
+{{- end}}
+</pre>
+{{- end}}
+</div>
+<div id="details"></div>
+<script>
+var tests = {{.Tests}};
+
+function lineTests(line) {
+	var attr = line.getAttribute("data-tests");
+	return attr ? attr.split(" ").map(Number) : [];
+}
+
+// clicking a line lists the tests which cover it
+document.querySelectorAll(".line").forEach(function(line) {
+	line.addEventListener("click", function() {
+		var details = document.getElementById("details");
+		var covering = lineTests(line);
+		details.textContent = "";
+		if (covering.length === 0) {
+			details.style.display = "none";
+			return;
+		}
+		covering.forEach(function(i) {
+			var entry = document.createElement("div");
+			entry.textContent = tests[i].name + (tests[i].file ? " (" + tests[i].file + ":" + tests[i].line + ")" : "");
+			details.appendChild(entry);
+		});
+		details.style.display = "block";
+	});
+});
+
+// selecting a test highlights every line it covers
+document.querySelectorAll("#tests li").forEach(function(item) {
+	item.addEventListener("click", function() {
+		var selected = Number(item.getAttribute("data-test"));
+		var deselect = item.classList.contains("selected");
+		document.querySelectorAll("#tests li").forEach(function(other) { other.classList.remove("selected"); });
+		document.querySelectorAll(".line").forEach(function(line) {
+			line.classList.toggle("highlighted", !deselect && lineTests(line).indexOf(selected) !== -1);
+		});
+		if (!deselect) {
+			item.classList.add("selected");
+		}
+	});
+});
+</script>
+</body>
+</html>
+`))
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ShawnROGrady/go-find-tests/cover"
+	"github.com/ShawnROGrady/go-find-tests/finder"
+	"github.com/ShawnROGrady/go-find-tests/tester"
+)
+
+func TestHTMLReport(t *testing.T) {
+	m := tester.Matrix{
+		Package: "github.com/ShawnROGrady/go-find-tests/testdata/kinds",
+		Tests:   []string{"Example_reverse", "TestReverseEmpty"},
+		Blocks: []cover.Block{
+			{File: "reverse.go", StartLine: 5, StartCol: 2, EndLine: 6, EndCol: 50, NumStmt: 2, Count: 2},
+			{File: "reverse.go", StartLine: 7, StartCol: 3, EndLine: 8, EndCol: 1, NumStmt: 1, Count: 0},
+			{File: "other.go", StartLine: 1, StartCol: 1, EndLine: 2, EndCol: 1, NumStmt: 1, Count: 1},
+		},
+		Counts: [][]int{{1, 0, 1}, {1, 0, 0}},
+	}
+	positions := map[string]finder.TestPosition{
+		"TestReverseEmpty": {File: "../../testdata/kinds/reverse_test.go", Line: 8, Col: 1},
+	}
+
+	report, err := newHTMLReport(m, "../../testdata/kinds", positions)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if len(report.Files) != 1 || report.Files[0].Name != "reverse.go" {
+		t.Fatalf("Unexpected files (expected only reverse.go, files of other packages are skipped): %v", report.Files)
+	}
+	expectedLines := map[int]struct {
+		class string
+		tests []int
+	}{
+		4: {class: "", tests: nil},
+		5: {class: "covered", tests: []int{0, 1}},
+		6: {class: "covered", tests: []int{0, 1}},
+		7: {class: "uncovered", tests: nil},
+	}
+	lines := report.Files[0].Lines
+	for n, expected := range expectedLines {
+		line := lines[n-1]
+		if line.Class() != expected.class || fmt.Sprint(line.Tests) != fmt.Sprint(expected.tests) {
+			t.Errorf("Unexpected line %d (expected class = '%s', tests = %v, actual class = '%s', tests = %v)", n, expected.class, expected.tests, line.Class(), line.Tests)
+		}
+	}
+	if test := report.Tests[1]; test.File != "reverse_test.go" || test.Line != 8 {
+		t.Errorf("Unexpected position of %s: %s:%d", test.Name, test.File, test.Line)
+	}
+
+	var b bytes.Buffer
+	if err := htmlTemplate.Execute(&b, report); err != nil {
+		t.Fatalf("Unexpected error rendering report: %s", err)
+	}
+	for _, expected := range []string{
+		`<span class="line covered" data-tests="0 1"><span class="num">6</span><span class="count">2</span>	for i, j := 0, len(r)-1; i &lt; j; i, j = i&#43;1, j-1 {</span>`,
+		`<li data-test="1">TestReverseEmpty <span class="pos">reverse_test.go:8</span></li>`,
+		`var tests = [{"name":"Example_reverse"},{"name":"TestReverseEmpty","file":"reverse_test.go","line":8}];`,
+	} {
+		if !strings.Contains(b.String(), expected) {
+			t.Errorf("Expected report to contain '%s'", expected)
+		}
+	}
+}
+
+func TestWriteHTMLFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "html")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	report := htmlReport{Package: "github.com/org/repo/pkg", Tests: []htmlTest{}, Files: []htmlFile{}}
+
+	var expected bytes.Buffer
+	if err := htmlTemplate.Execute(&expected, report); err != nil {
+		t.Fatalf("Unexpected error rendering report: %s", err)
+	}
+	path := filepath.Join(dir, "report.html")
+	if err := writeHTMLFile(path, report); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if actual, err := ioutil.ReadFile(path); err != nil || string(actual) != expected.String() {
+		t.Errorf("Unexpected report file (err = %v, len = %d, expected len = %d)", err, len(actual), expected.Len())
+	}
+
+	if err := writeHTMLFile(filepath.Join(dir, "missing", "report.html"), report); err == nil {
+		t.Error("Unexpectedly no error writing to a missing directory")
+	}
+}
@@ -0,0 +1,316 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ShawnROGrady/go-find-tests/index"
+	"github.com/ShawnROGrady/go-find-tests/tester"
+)
+
+// defaultIndexFile is the name of the index file in the root of the module if no path is provided
+const defaultIndexFile = ".go-find-tests.index"
+
+var indexCommand = command{
+	usage:       "[dir]",
+	description: "runs every test of every package of the module once and writes an index of the blocks each test covers, only re-running packages whose inputs changed since the last index",
+	flags: func(fs *flag.FlagSet) func(args []string, jsonFmt bool, dst io.Writer) error {
+		var (
+			testerFlags = addTesterFlags(fs)
+			out         = fs.String("o", "", "File to write the index to, if empty the 'index' of "+configFile+" or '"+defaultIndexFile+"' in the root of the module is used")
+			full        = fs.Bool("full", false, "Re-index every package, even if it is unchanged since the existing index was written")
+		)
+		return func(args []string, jsonFmt bool, dst io.Writer) error {
+			dir := "."
+			switch len(args) {
+			case 0:
+			case 1:
+				dir = args[0]
+			default:
+				return &usageErr{msg: "At most one directory argument expected"}
+			}
+			path, err := indexPath(*out, dir)
+			if err != nil {
+				return err
+			}
+
+			var prev *index.Index
+			if !*full {
+				prev, err = readIndex(path)
+				if err != nil && !os.IsNotExist(err) {
+					fmt.Fprintf(os.Stderr, "Ignoring existing index: %s\n", err)
+				}
+			}
+
+			summary := indexSummary{Reindexed: []string{}, Failed: []indexFailure{}}
+			idx, err := index.Build(dir, testerFlags.config(), prev, func(pkg string, reused bool, err error) {
+				switch {
+				case err != nil:
+					summary.Failed = append(summary.Failed, indexFailure{Package: pkg, Error: err.Error()})
+				case reused:
+					summary.Unchanged++
+				default:
+					summary.Reindexed = append(summary.Reindexed, pkg)
+				}
+			})
+			if err != nil {
+				return fmt.Errorf("Error building index: %w", err)
+			}
+			if err := writeIndex(path, idx); err != nil {
+				return fmt.Errorf("Error writing index: %w", err)
+			}
+
+			summary.Index = path
+			if err := printIndexSummary(dst, summary, jsonFmt); err != nil {
+				return fmt.Errorf("Error writing output: %w", err)
+			}
+			if len(summary.Failed) != 0 {
+				return fmt.Errorf("%d package(s) could not be indexed", len(summary.Failed))
+			}
+			return nil
+		}
+	},
+}
+
+var queryCommand = command{
+	usage:       "filepath:line[.col]|filepath:start-end|-symbol name|-test name",
+	description: "prints the tests which cover a position, range of lines, or function using only the index written by the index command, or with -test the blocks covered by a test",
+	flags: func(fs *flag.FlagSet) func(args []string, jsonFmt bool, dst io.Writer) error {
+		var (
+			indexFile = fs.String("index", "", "The index file to query, if empty the 'index' of "+configFile+" or '"+defaultIndexFile+"' in the root of the current module is used")
+			symbol    = fs.String("symbol", "", "Query the tests which cover a function or method, e.g. 'New', 'cover.New', or 'cover.(*Profile).Covers'")
+			test      = fs.String("test", "", "Query the blocks covered by a top-level test, optionally qualified by its import path e.g. 'github.com/org/repo/pkg.TestName'")
+		)
+		return func(args []string, jsonFmt bool, dst io.Writer) error {
+			queries := len(args)
+			if *symbol != "" {
+				queries++
+			}
+			if *test != "" {
+				queries++
+			}
+			if queries != 1 {
+				return &usageErr{msg: "Exactly one of a position, line range, -symbol, or -test is required"}
+			}
+
+			path, err := indexPath(*indexFile, ".")
+			if err != nil {
+				return err
+			}
+			idx, err := readIndex(path)
+			if err != nil {
+				return fmt.Errorf("Error reading index (run the index command to create it): %w", err)
+			}
+
+			var hits []index.Hit
+			switch {
+			case *test != "":
+				coverage, err := idx.Test(*test)
+				if err != nil {
+					return err
+				}
+				if err := printCoverage(dst, coverage, jsonFmt); err != nil {
+					return fmt.Errorf("Error writing output: %w", err)
+				}
+				return nil
+			case *symbol != "":
+				hits, err = idx.Symbol(*symbol)
+				if err != nil {
+					return err
+				}
+			default:
+				file, start, end, col, err := parseQueryPosition(args[0])
+				if err != nil {
+					return &usageErr{msg: fmt.Sprintf("Error parsing position arg: %s", err)}
+				}
+				file = indexFilePath(idx, file)
+				if start == end {
+					hits = idx.Position(file, start, col)
+				} else {
+					hits = idx.Lines(file, start, end)
+				}
+			}
+
+			byPackage := make(map[string][]string)
+			for _, hit := range hits {
+				byPackage[hit.Package] = append(byPackage[hit.Package], hit.Test)
+			}
+			if err := printPackageTests(dst, byPackage, jsonFmt); err != nil {
+				return fmt.Errorf("Error writing output: %w", err)
+			}
+			return nil
+		}
+	},
+}
+
+// parseQueryPosition parses either 'file:line[.col]' or 'file:start-end'
+func parseQueryPosition(arg string) (file string, start, end, col int, err error) {
+	dir, lines, err := parseMinimizeTarget(arg)
+	if err != nil {
+		return "", 0, 0, 0, err
+	}
+	if lines != nil {
+		return dir + lines.File, lines.Start, lines.End, 0, nil
+	}
+	pos, err := parsePosition(arg)
+	if err != nil {
+		return "", 0, 0, 0, err
+	}
+	return pos.file, pos.line, pos.line, pos.col, nil
+}
+
+// indexPath returns the path of the index file, defaulting to the path in the configuration file
+// or otherwise the root of the module which owns dir
+func indexPath(path, dir string) (string, error) {
+	if path != "" {
+		return path, nil
+	}
+	root := tester.ModuleRoot(dir)
+	if root == "" {
+		return "", &usageErr{msg: fmt.Sprintf("No go.mod found at or above '%s', provide the path of the index", dir)}
+	}
+	conf, err := readProjectConfig(root)
+	if err != nil {
+		return "", err
+	}
+	if conf != nil && conf.Index != nil && *conf.Index != "" {
+		if filepath.IsAbs(*conf.Index) {
+			return *conf.Index, nil
+		}
+		return filepath.Join(root, filepath.FromSlash(*conf.Index)), nil
+	}
+	return filepath.Join(root, defaultIndexFile), nil
+}
+
+// indexFilePath converts a file argument to the form used by the index, i.e. slash separated and relative to the module root
+// the file may be qualified by the module path, absolute, relative to the working directory, or relative to the module root
+func indexFilePath(idx *index.Index, file string) string {
+	slashed := filepath.ToSlash(file)
+	if idx.Module != "" && strings.HasPrefix(slashed, idx.Module+"/") {
+		return strings.TrimPrefix(slashed, idx.Module+"/")
+	}
+
+	root := tester.ModuleRoot(".")
+	if root == "" {
+		return slashed
+	}
+	abs := file
+	if !filepath.IsAbs(abs) {
+		if _, err := os.Stat(file); err != nil {
+			// not relative to the working directory
+			return slashed
+		}
+		var err error
+		if abs, err = filepath.Abs(file); err != nil {
+			return slashed
+		}
+	}
+	if rel, err := filepath.Rel(root, abs); err == nil && !strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(rel)
+	}
+	return slashed
+}
+
+func readIndex(path string) (*index.Index, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return index.Read(f)
+}
+
+// writeIndex replaces the index file, so an interrupted write never leaves a truncated index
+func writeIndex(path string, idx *index.Index) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := idx.Write(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+type indexSummary struct {
+	Index     string         `json:"index"`
+	Reindexed []string       `json:"reindexed"`
+	Unchanged int            `json:"unchanged"`
+	Failed    []indexFailure `json:"failed"`
+}
+
+type indexFailure struct {
+	Package string `json:"package"`
+	Error   string `json:"error"`
+}
+
+func printIndexSummary(dst io.Writer, summary indexSummary, jsonFmt bool) error {
+	if jsonFmt {
+		b, err := json.Marshal(summary)
+		if err != nil {
+			return err
+		}
+		_, err = dst.Write(b)
+		return err
+	}
+
+	for _, pkg := range summary.Reindexed {
+		if _, err := fmt.Fprintf(dst, "indexed %s\n", pkg); err != nil {
+			return err
+		}
+	}
+	for _, failure := range summary.Failed {
+		if _, err := fmt.Fprintf(dst, "failed %s: %s\n", failure.Package, failure.Error); err != nil {
+			return err
+		}
+	}
+	if len(summary.Reindexed) != 0 || len(summary.Failed) != 0 {
+		if _, err := fmt.Fprint(dst, "\n"); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(dst, "%d indexed, %d unchanged, %d failed: %s\n", len(summary.Reindexed), summary.Unchanged, len(summary.Failed), summary.Index)
+	return err
+}
+
+// printCoverage prints the blocks covered by each test, each line of the form '<file>:<start line>.<start col>,<end line>.<end col> <count>'
+func printCoverage(dst io.Writer, coverage []index.Coverage, jsonFmt bool) error {
+	if jsonFmt {
+		b, err := json.Marshal(coverage)
+		if err != nil {
+			return err
+		}
+		_, err = dst.Write(b)
+		return err
+	}
+
+	for _, c := range coverage {
+		if _, err := fmt.Fprintf(dst, "%s %s\n", c.Package, c.Test); err != nil {
+			return err
+		}
+		for _, b := range c.Blocks {
+			if _, err := fmt.Fprintf(dst, "\t%s:%d.%d,%d.%d %d\n", b.File, b.StartLine, b.StartCol, b.EndLine, b.EndCol, b.Count); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ShawnROGrady/go-find-tests/cover"
+	"github.com/ShawnROGrady/go-find-tests/index"
+)
+
+var parseQueryPositionTests = map[string]struct {
+	providedArg   string
+	expectedFile  string
+	expectedStart int
+	expectedEnd   int
+	expectedCol   int
+	expectErr     bool
+}{
+	"line": {
+		providedArg:   "cover/profile.go:10",
+		expectedFile:  "cover/profile.go",
+		expectedStart: 10,
+		expectedEnd:   10,
+	},
+	"line_and_col": {
+		providedArg:   "cover/profile.go:10.5",
+		expectedFile:  "cover/profile.go",
+		expectedStart: 10,
+		expectedEnd:   10,
+		expectedCol:   5,
+	},
+	"range": {
+		providedArg:   "cover/profile.go:10-20",
+		expectedFile:  "cover/profile.go",
+		expectedStart: 10,
+		expectedEnd:   20,
+	},
+	"reversed_range": {
+		providedArg: "cover/profile.go:20-10",
+		expectErr:   true,
+	},
+	"no_line": {
+		providedArg: "cover/profile.go",
+		expectErr:   true,
+	},
+}
+
+func TestParseQueryPosition(t *testing.T) {
+	for testName, test := range parseQueryPositionTests {
+		test := test
+		t.Run(testName, func(t *testing.T) {
+			file, start, end, col, err := parseQueryPosition(test.providedArg)
+			if test.expectErr {
+				if err == nil {
+					t.Errorf("Unexpectedly no error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if file != test.expectedFile || start != test.expectedStart || end != test.expectedEnd || col != test.expectedCol {
+				t.Errorf("Unexpected position (expected = %s:%d-%d.%d, actual = %s:%d-%d.%d)", test.expectedFile, test.expectedStart, test.expectedEnd, test.expectedCol, file, start, end, col)
+			}
+		})
+	}
+}
+
+func TestIndexFilePath(t *testing.T) {
+	idx := &index.Index{Module: "github.com/ShawnROGrady/go-find-tests"}
+	tests := map[string]string{
+		"github.com/ShawnROGrady/go-find-tests/cover/profile.go": "cover/profile.go",
+		"index_test.go":          "cmd/go-find-tests/index_test.go",
+		"../../cover/profile.go": "cover/profile.go",
+		"cover/missing.go":       "cover/missing.go",
+	}
+	for file, expected := range tests {
+		if actual := indexFilePath(idx, file); actual != expected {
+			t.Errorf("Unexpected path of %s (expected = %s, actual = %s)", file, expected, actual)
+		}
+	}
+}
+
+func TestPrintCoverage(t *testing.T) {
+	coverage := []index.Coverage{{
+		Package: "example.com/pkg",
+		Test:    "TestA",
+		Blocks:  []cover.Block{{File: "a.go", StartLine: 3, StartCol: 1, EndLine: 4, EndCol: 2, NumStmt: 2, Count: 4}},
+	}}
+	expected := map[bool]string{
+		false: "example.com/pkg TestA\n\ta.go:3.1,4.2 4\n",
+		true:  `[{"package":"example.com/pkg","test":"TestA","blocks":[{"file":"a.go","start_line":3,"start_col":1,"end_line":4,"end_col":2,"num_stmt":2,"count":4}]}]`,
+	}
+	for jsonFmt, expectedOutput := range expected {
+		var b bytes.Buffer
+		if err := printCoverage(&b, coverage, jsonFmt); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if output := b.String(); output != expectedOutput {
+			t.Errorf("Unexpected output with json = %t (expected = '%s', actual = '%s')", jsonFmt, expectedOutput, output)
+		}
+	}
+}
@@ -4,10 +4,10 @@ import (
 	"errors"
 	"flag"
 	"fmt"
-	"log"
 	"os"
 	"regexp"
 	"strconv"
+	"time"
 
 	"github.com/ShawnROGrady/go-find-tests/tester"
 )
@@ -17,20 +17,50 @@ const (
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		if cmd, ok := commands[os.Args[1]]; ok {
+			os.Exit(runCommand(os.Args[1], cmd, os.Args[2:], os.Stdout))
+		}
+	}
+
 	var (
 		includeSubtests = flag.Bool("include-subs", false, "Find specific sub-tests which cover the specified block")
 		short           = flag.Bool("short", false, "Sets '-short' flag when testing for coverage")
-		runExpr         = flag.String("run", ".", "Check only top-level tests matching the regular expression")
+		runExpr         = flag.String("run", ".", runUsage)
+		skipExpr        = flag.String("skip", "", skipUsage)
 		printPositions  = flag.Bool("print-positions", false, "Print the positions of the found tests")
 		jsonFmt         = flag.Bool("json", false, "Print the output in json format")
-		lineFmt         = flag.String("line-fmt", defaultLineFmt, "With -print-positions: the fmt to use when writing the postions of found tests. Structure:\n\t\t'%t': test name\n\t\t'%f': file\n\t\t'%l': line\n\t\t'%c': column\n\t\t'%o': offset\n\t'%s': subtests (printed as comma separated list)")
+		lineFmt         = flag.String("line-fmt", defaultLineFmt, "With -print-positions: the fmt to use when writing the postions of found tests. Structure:\n\t\t'%t': test name\n\t\t'%f': file\n\t\t'%l': line\n\t\t'%c': column\n\t\t'%o': offset\n\t'%s': subtests (printed as comma separated list)\n\t\t'%d': elapsed time\n\t\t'%n': total statements covered\n\t\t'%h': hits of the position\n\t\t'%S': status of the test")
 		runSeq          = flag.Bool("seq", false, "Run all tests sequentially. Greatly reduces performance but may be necessary for integration tests")
+		workDeps        = flag.Bool("work-deps", false, "In a go.work workspace, also check the tests of other workspace modules which depend on the package")
+		benchmarks      = flag.Bool("bench", false, "Also check benchmarks, each run for a single iteration")
+		sortOrder       = flag.String("sort", "", "Order results by 'name', 'duration' (fastest first), 'specificity' (fewest statements covered first), or 'hits' (most hits first), and show those metrics")
+		execTests       = flag.Bool("exec", false, "Run the covering tests with 'go test -v', arguments following the position are passed to 'go test'")
+		runPattern      = flag.Bool("run-pattern", false, "Print the '-run' (and if shorter '-skip') arguments to 'go test' which select exactly the covering tests")
+		watchSource     = flag.Bool("watch", false, "Keep running, printing the updated covering tests each time the source of the package changes")
+		watchInterval   = flag.Duration("watch-interval", time.Second, "With -watch: how often the source of the package is checked for changes")
+		runs            = flag.Int("runs", 1, "Run each test the specified number of times, reporting tests which cover the position in only some runs as unstable")
+		isolate         = flag.Bool("isolate", false, isolateUsage)
+		copyPackage     = flag.Bool("copy-pkg", false, copyPackageUsage)
+		env             envFlag
+		serial          serialFlag
+		groups          groupFlag
+		timeout         = flag.Duration("timeout", 0, "Sets '-timeout' when running each test, if 0 the default of the testing package is used")
+		tags            = flag.String("tags", "", tagsUsage)
+		keep            = flag.String("keep", "", keepUsage)
+		testExec        = flag.String("test-exec", "", testExecUsage)
+		includeSetup    = flag.Bool("include-setup", false, "Report every test as covering positions covered by package initialization or TestMain, instead of only the tests which cover them more often")
+		includeFailing  = flag.Bool("include-failing", false, "Report tests which fail after covering the position instead of exiting with an error, printing the status of each test")
+		includeSkipped  = flag.Bool("include-skipped", false, "Report tests which are skipped after covering the position, e.g. with -short, printing the status of each test")
 		helpShort       = flag.Bool("h", false, "Print a help message and exit")
 		help            = flag.Bool("help", false, "Print a help message and exit")
 	)
+	flag.Var(&env, "env", envUsage)
+	flag.Var(&serial, "serial", serialUsage)
+	flag.Var(&groups, "group", groupUsage)
 	flag.Parse()
 	if *help || *helpShort {
-		fmt.Fprintf(os.Stdout, "Usage: %s [-include-subs] [-short] [-run regexp] [-json|-line-fmt regexp] filepath:line[.col]\n", os.Args[0])
+		fmt.Fprintf(os.Stdout, "Usage: %s [-include-subs] [-short] [-run regexp] [-json|-line-fmt regexp] [-exec] filepath:line[.col] [go test flags]\n", os.Args[0])
 		fmt.Fprintf(os.Stdout, "Description: %s prints the tests (and optionally sub tests) which cover a specified block of code\n", os.Args[0])
 		fmt.Fprint(os.Stdout, "Required arguments:\n")
 		fmt.Fprint(os.Stdout, "\tfilepath: path to the file to check\n")
@@ -41,34 +71,76 @@ func main() {
 		flag.VisitAll(func(f *flag.Flag) {
 			fmt.Fprintf(os.Stdout, "\t-%s: %s [default = %v]\n", f.Name, f.Usage, f.DefValue)
 		})
+		fmt.Fprintf(os.Stdout, "Commands (see '%s <command> -h'):\n", os.Args[0])
+		for _, name := range commandNames() {
+			fmt.Fprintf(os.Stdout, "\t%s: %s\n", name, commands[name].description)
+		}
 		os.Exit(0)
 	}
 
 	args := flag.Args()
 	if len(args) == 0 {
-		log.Fatal("Position argument (fmt = 'file:line[.col]') required")
+		os.Exit(printError(os.Stderr, &usageErr{msg: "Position argument (fmt = 'file:line[.col]') required"}, *jsonFmt))
+	}
+	if err := applyConfig(flag.CommandLine, configTarget(args)); err != nil {
+		os.Exit(printError(os.Stderr, err, *jsonFmt))
 	}
 
 	pos, err := parsePosition(args[0])
 	if err != nil {
-		log.Fatalf("Error parsing position arg: %s", err)
+		os.Exit(printError(os.Stderr, &usageErr{msg: fmt.Sprintf("Error parsing position arg: %s", err)}, *jsonFmt))
+	}
+
+	if *runs < 1 {
+		os.Exit(printError(os.Stderr, &usageErr{msg: "-runs must be at least 1"}, *jsonFmt))
+	}
+
+	var order tester.SortOrder
+	if *sortOrder != "" {
+		order, err = tester.ParseSortOrder(*sortOrder)
+		if err != nil {
+			os.Exit(printError(os.Stderr, &usageErr{msg: err.Error()}, *jsonFmt))
+		}
 	}
 
 	conf := runConfig{
 		testerConf: tester.Config{
-			IncludeSubtests: *includeSubtests,
-			Short:           *short,
-			Run:             *runExpr,
-			Seq:             *runSeq,
+			IncludeSubtests:     *includeSubtests,
+			Short:               *short,
+			Run:                 *runExpr,
+			Skip:                *skipExpr,
+			Seq:                 *runSeq,
+			Timeout:             *timeout,
+			Tags:                *tags,
+			KeepDir:             *keep,
+			ExecWrapper:         *testExec,
+			IncludeSetup:        *includeSetup,
+			IncludeFailing:      *includeFailing,
+			IncludeSkipped:      *includeSkipped,
+			WorkspaceDependents: *workDeps,
+			Benchmarks:          *benchmarks,
+			Runs:                *runs,
+			Isolate:             *isolate,
+			CopyPackage:         *copyPackage,
+			Env:                 env,
+			Serial:              serial,
+			Groups:              groups,
 		},
 		jsonFmt:        *jsonFmt,
 		lineFmt:        *lineFmt,
 		printPositions: *printPositions,
+		sortOrder:      order,
+		exec:           *execTests,
+		runPattern:     *runPattern,
+		watch:          *watchSource,
+		watchInterval:  *watchInterval,
+		goTestArgs:     args[1:],
 	}
 
 	if err := run(conf, pos.file, pos.line, pos.col, os.Stdout); err != nil {
-		log.Fatal(err)
+		os.Exit(exitCode(os.Stderr, err, *jsonFmt))
 	}
+	os.Exit(exitOK)
 }
 
 type pos struct {
@@ -77,7 +149,7 @@ type pos struct {
 }
 
 func parsePosition(arg string) (*pos, error) {
-	argFmt := `^([a-zA-Z0-9\/\.\-_]+.go):([0-9]+)(?:\.([0-9]+))?`
+	argFmt := `^(.+\.go):(\d+)(?:\.(\d+))?$`
 	argReg := regexp.MustCompile(argFmt)
 
 	subexps := argReg.FindStringSubmatch(arg)
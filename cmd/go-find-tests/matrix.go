@@ -0,0 +1,151 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/ShawnROGrady/go-find-tests/cover"
+	"github.com/ShawnROGrady/go-find-tests/tester"
+)
+
+// formats of the coverage matrix
+const (
+	matrixJSON    = "json"
+	matrixCSV     = "csv"
+	matrixCompact = "compact"
+)
+
+var matrixCommand = command{
+	usage:       "package",
+	description: "runs every test of the package once and prints the coverage of every block by every test",
+	flags: func(fs *flag.FlagSet) func(args []string, jsonFmt bool, dst io.Writer) error {
+		var (
+			testerFlags = addTesterFlags(fs)
+			format      = fs.String("format", matrixJSON, "The format of the matrix: 'json', 'csv' (one row per block, one column per test), or 'compact' (stable line based text, suitable for committing)")
+		)
+		return func(args []string, jsonFmt bool, dst io.Writer) error {
+			if len(args) != 1 {
+				return &usageErr{msg: "Package argument required"}
+			}
+			write, ok := matrixWriters[*format]
+			if !ok {
+				return &usageErr{msg: fmt.Sprintf("Unknown matrix format '%s' (expected one of: %s, %s, %s)", *format, matrixJSON, matrixCSV, matrixCompact)}
+			}
+
+			t, err := tester.NewPackage(args[0], testerFlags.config())
+			if err != nil {
+				return fmt.Errorf("Error constructing tester: %w", err)
+			}
+			profiles, err := t.Profiles()
+			if err != nil {
+				return fmt.Errorf("Error collecting test profiles: %w", err)
+			}
+
+			m := tester.NewMatrix(profiles)
+			m.Package = t.Package()
+			if err := write(dst, m); err != nil {
+				return fmt.Errorf("Error writing output: %w", err)
+			}
+			return nil
+		}
+	},
+}
+
+var matrixWriters = map[string]func(dst io.Writer, m tester.Matrix) error{
+	matrixJSON:    writeMatrixJSON,
+	matrixCSV:     writeMatrixCSV,
+	matrixCompact: writeMatrixCompact,
+}
+
+type matrixOutput struct {
+	Package string        `json:"package"`
+	Tests   []string      `json:"tests"`
+	Blocks  []matrixBlock `json:"blocks"`
+}
+
+type matrixBlock struct {
+	cover.Block
+	Tests map[string]int `json:"tests,omitempty"` // count of each test which executed the block
+}
+
+func writeMatrixJSON(dst io.Writer, m tester.Matrix) error {
+	out := matrixOutput{
+		Package: m.Package,
+		Tests:   m.Tests,
+		Blocks:  make([]matrixBlock, len(m.Blocks)),
+	}
+	for j := range m.Blocks {
+		out.Blocks[j].Block = m.Blocks[j]
+		for i := range m.Tests {
+			if count := m.Counts[i][j]; count != 0 {
+				if out.Blocks[j].Tests == nil {
+					out.Blocks[j].Tests = make(map[string]int)
+				}
+				out.Blocks[j].Tests[m.Tests[i]] = count
+			}
+		}
+	}
+
+	b, err := json.Marshal(out)
+	if err != nil {
+		return err
+	}
+	_, err = dst.Write(b)
+	return err
+}
+
+func writeMatrixCSV(dst io.Writer, m tester.Matrix) error {
+	w := csv.NewWriter(dst)
+	header := append([]string{"file", "start_line", "start_col", "end_line", "end_col", "num_stmt", "count"}, m.Tests...)
+	if err := w.Write(header); err != nil {
+		return err
+	}
+	for j, b := range m.Blocks {
+		row := []string{
+			b.File,
+			strconv.Itoa(b.StartLine),
+			strconv.Itoa(b.StartCol),
+			strconv.Itoa(b.EndLine),
+			strconv.Itoa(b.EndCol),
+			strconv.Itoa(b.NumStmt),
+			strconv.Itoa(b.Count),
+		}
+		for i := range m.Tests {
+			row = append(row, strconv.Itoa(m.Counts[i][j]))
+		}
+		if err := w.Write(row); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// writeMatrixCompact writes the matrix as sparse, line based text
+// output only depends on the coverage of the tests so it is stable between runs:
+//
+//	package <import path>
+//	test <index> <name>
+//	block <file>:<start line>.<start col>,<end line>.<end col> <statements> [<test index>:<count>...]
+func writeMatrixCompact(dst io.Writer, m tester.Matrix) error {
+	w := bufio.NewWriter(dst)
+	fmt.Fprintf(w, "package %s\n", m.Package)
+	for i, test := range m.Tests {
+		fmt.Fprintf(w, "test %d %s\n", i, test)
+	}
+	for j, b := range m.Blocks {
+		fmt.Fprintf(w, "block %s:%d.%d,%d.%d %d", b.File, b.StartLine, b.StartCol, b.EndLine, b.EndCol, b.NumStmt)
+		for i := range m.Tests {
+			if count := m.Counts[i][j]; count != 0 {
+				fmt.Fprintf(w, " %d:%d", i, count)
+			}
+		}
+		fmt.Fprint(w, "\n")
+	}
+	return w.Flush()
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ShawnROGrady/go-find-tests/cover"
+	"github.com/ShawnROGrady/go-find-tests/tester"
+)
+
+var testMatrix = tester.Matrix{
+	Package: "example.com/pkg",
+	Tests:   []string{"TestA", "TestB"},
+	Blocks: []cover.Block{
+		{File: "a.go", StartLine: 3, StartCol: 1, EndLine: 4, EndCol: 1, NumStmt: 2, Count: 5},
+		{File: "a.go", StartLine: 7, StartCol: 1, EndLine: 8, EndCol: 1, NumStmt: 3, Count: 0},
+	},
+	Counts: [][]int{{4, 0}, {1, 0}},
+}
+
+var matrixWriterTests = map[string]string{
+	matrixJSON: `{"package":"example.com/pkg","tests":["TestA","TestB"],"blocks":[` +
+		`{"file":"a.go","start_line":3,"start_col":1,"end_line":4,"end_col":1,"num_stmt":2,"count":5,"tests":{"TestA":4,"TestB":1}},` +
+		`{"file":"a.go","start_line":7,"start_col":1,"end_line":8,"end_col":1,"num_stmt":3,"count":0}]}`,
+	matrixCSV: "file,start_line,start_col,end_line,end_col,num_stmt,count,TestA,TestB\n" +
+		"a.go,3,1,4,1,2,5,4,1\n" +
+		"a.go,7,1,8,1,3,0,0,0\n",
+	matrixCompact: "package example.com/pkg\n" +
+		"test 0 TestA\n" +
+		"test 1 TestB\n" +
+		"block a.go:3.1,4.1 2 0:4 1:1\n" +
+		"block a.go:7.1,8.1 3\n",
+}
+
+func TestMatrixWriters(t *testing.T) {
+	for format, expectedOutput := range matrixWriterTests {
+		t.Run(format, func(t *testing.T) {
+			var b bytes.Buffer
+			if err := matrixWriters[format](&b, testMatrix); err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if output := b.String(); output != expectedOutput {
+				t.Errorf("Unexpected output (expected = '%s', actual = '%s')", expectedOutput, output)
+			}
+		})
+	}
+}
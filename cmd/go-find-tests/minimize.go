@@ -0,0 +1,119 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strconv"
+
+	"github.com/ShawnROGrady/go-find-tests/tester"
+)
+
+var minimizeCommand = command{
+	usage:       "package|filepath:start[-end]",
+	description: "prints a small set of tests which together cover every statement covered by the full suite of the package, optionally only considering a range of lines",
+	flags: func(fs *flag.FlagSet) func(args []string, jsonFmt bool, dst io.Writer) error {
+		testerFlags := addTesterFlags(fs)
+		return func(args []string, jsonFmt bool, dst io.Writer) error {
+			if len(args) != 1 {
+				return &usageErr{msg: "Package or line range argument (fmt = 'filepath:start[-end]') required"}
+			}
+			pkg, lines, err := parseMinimizeTarget(args[0])
+			if err != nil {
+				return &usageErr{msg: fmt.Sprintf("Error parsing line range arg: %s", err)}
+			}
+			return minimize(testerFlags.config(), pkg, lines, jsonFmt, dst)
+		}
+	},
+}
+
+var lineRangeReg = regexp.MustCompile(`^(.+\.go):([0-9]+)(?:-([0-9]+))?$`)
+
+// parseMinimizeTarget parses either a package or a range of lines of a file
+func parseMinimizeTarget(arg string) (string, *tester.LineRange, error) {
+	subexps := lineRangeReg.FindStringSubmatch(arg)
+	if len(subexps) == 0 {
+		return arg, nil, nil
+	}
+
+	start, err := strconv.Atoi(subexps[2])
+	if err != nil {
+		return "", nil, err
+	}
+	end := start
+	if subexps[3] != "" {
+		end, err = strconv.Atoi(subexps[3])
+		if err != nil {
+			return "", nil, err
+		}
+	}
+	if end < start {
+		return "", nil, errors.New("end of line range is before start")
+	}
+
+	dir, file := filepath.Split(subexps[1])
+	return dir, &tester.LineRange{File: file, Start: start, End: end}, nil
+}
+
+func minimize(conf tester.Config, pkg string, lines *tester.LineRange, jsonFmt bool, dst io.Writer) error {
+	t, err := tester.NewPackage(pkg, conf)
+	if err != nil {
+		return fmt.Errorf("Error constructing tester: %w", err)
+	}
+
+	profiles, err := t.Profiles()
+	if err != nil {
+		return fmt.Errorf("Error collecting test profiles: %w", err)
+	}
+
+	if err := printMinimized(dst, tester.Minimize(profiles, lines), jsonFmt); err != nil {
+		return fmt.Errorf("Error writing output: %w", err)
+	}
+	return nil
+}
+
+type minimizedOutput struct {
+	Tests        []string `json:"tests"`
+	Statements   int      `json:"statements"`
+	Retained     int      `json:"retained"`
+	Elapsed      string   `json:"elapsed"`
+	SuiteElapsed string   `json:"suite_elapsed"`
+	Saved        string   `json:"saved"`
+}
+
+func printMinimized(dst io.Writer, min tester.Minimized, jsonFmt bool) error {
+	tests := make([]string, len(min.Tests))
+	for i := range min.Tests {
+		tests[i] = min.Tests[i].Name
+	}
+
+	if jsonFmt {
+		b, err := json.Marshal(minimizedOutput{
+			Tests:        tests,
+			Statements:   min.Statements,
+			Retained:     min.Retained,
+			Elapsed:      min.Elapsed.String(),
+			SuiteElapsed: min.SuiteElapsed.String(),
+			Saved:        min.Saved().String(),
+		})
+		if err != nil {
+			return err
+		}
+		_, err = dst.Write(b)
+		return err
+	}
+
+	if err := printTests(dst, tests, false); err != nil {
+		return err
+	}
+	retained := 100.0
+	if min.Statements != 0 {
+		retained = 100 * float64(min.Retained) / float64(min.Statements)
+	}
+	_, err := fmt.Fprintf(dst, "\ncoverage retained: %d/%d statements (%.1f%%)\ntime: %s of %s (saved %s)\n", min.Retained, min.Statements, retained, min.Elapsed, min.SuiteElapsed, min.Saved())
+	return err
+}
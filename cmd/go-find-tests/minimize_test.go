@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/ShawnROGrady/go-find-tests/tester"
+)
+
+var parseMinimizeTargetTests = map[string]struct {
+	providedArg   string
+	expectedPkg   string
+	expectedLines *tester.LineRange
+	expectErr     bool
+}{
+	"package": {
+		providedArg: "./cover",
+		expectedPkg: "./cover",
+	},
+	"import_path": {
+		providedArg: "github.com/ShawnROGrady/go-find-tests/cover",
+		expectedPkg: "github.com/ShawnROGrady/go-find-tests/cover",
+	},
+	"line_range": {
+		providedArg:   "./cover/profile.go:10-20",
+		expectedPkg:   "./cover/",
+		expectedLines: &tester.LineRange{File: "profile.go", Start: 10, End: 20},
+	},
+	"single_line": {
+		providedArg:   "profile.go:10",
+		expectedPkg:   "",
+		expectedLines: &tester.LineRange{File: "profile.go", Start: 10, End: 10},
+	},
+	"reversed_range": {
+		providedArg: "./cover/profile.go:20-10",
+		expectErr:   true,
+	},
+}
+
+func TestParseMinimizeTarget(t *testing.T) {
+	for testName, test := range parseMinimizeTargetTests {
+		t.Run(testName, func(t *testing.T) {
+			pkg, lines, err := parseMinimizeTarget(test.providedArg)
+			if err != nil {
+				if !test.expectErr {
+					t.Fatalf("Unexpected error: %s", err)
+				}
+				return
+			} else if test.expectErr {
+				t.Fatalf("Unexpectedly no error")
+			}
+
+			if pkg != test.expectedPkg {
+				t.Errorf("Unexpected package (expected = '%s', actual = '%s')", test.expectedPkg, pkg)
+			}
+			if (lines == nil) != (test.expectedLines == nil) || (lines != nil && *lines != *test.expectedLines) {
+				t.Errorf("Unexpected line range (expected = %v, actual = %v)", test.expectedLines, lines)
+			}
+		})
+	}
+}
+
+var printMinimizedTests = map[string]struct {
+	jsonFmt        bool
+	expectedOutput string
+}{
+	"plain": {
+		jsonFmt:        false,
+		expectedOutput: "TestA\nTestB\n\ncoverage retained: 30/30 statements (100.0%)\ntime: 30ms of 1.03s (saved 1s)\n",
+	},
+	"json": {
+		jsonFmt:        true,
+		expectedOutput: `{"tests":["TestA","TestB"],"statements":30,"retained":30,"elapsed":"30ms","suite_elapsed":"1.03s","saved":"1s"}`,
+	},
+}
+
+func TestPrintMinimized(t *testing.T) {
+	min := tester.Minimized{
+		Tests: []tester.TestProfile{
+			{Name: "TestA", Elapsed: 10 * time.Millisecond},
+			{Name: "TestB", Elapsed: 20 * time.Millisecond},
+		},
+		Statements:   30,
+		Retained:     30,
+		Elapsed:      30 * time.Millisecond,
+		SuiteElapsed: 1030 * time.Millisecond,
+	}
+	for testName, test := range printMinimizedTests {
+		t.Run(testName, func(t *testing.T) {
+			var b bytes.Buffer
+			if err := printMinimized(&b, min, test.jsonFmt); err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if output := b.String(); output != test.expectedOutput {
+				t.Errorf("Unexpected output (expected = '%s', actual = '%s')", test.expectedOutput, output)
+			}
+		})
+	}
+}
@@ -4,10 +4,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"sort"
 	"strconv"
 	"strings"
+	"text/tabwriter"
 
 	"github.com/ShawnROGrady/go-find-tests/finder"
+	"github.com/ShawnROGrady/go-find-tests/tester"
 )
 
 func printTests(dst io.Writer, tests []string, jsonFmt bool) error {
@@ -27,9 +30,199 @@ func printTests(dst io.Writer, tests []string, jsonFmt bool) error {
 	return nil
 }
 
+// metrics are the measurements of a test run used to rank covering tests
+type metrics struct {
+	Elapsed    string        `json:"elapsed"`
+	Statements int           `json:"statements"`           // total statements covered
+	Hits       int           `json:"hits"`                 // number of times the position was executed
+	SetupHits  int           `json:"setup_hits,omitempty"` // number of times package initialization or TestMain executed the position, excluded from Hits
+	Status     tester.Status `json:"status"`
+}
+
+func newMetrics(result tester.Result) metrics {
+	return metrics{
+		Elapsed:    result.Elapsed.String(),
+		Statements: result.Statements,
+		Hits:       result.Hits(),
+		SetupHits:  result.SetupHits,
+		Status:     result.Status,
+	}
+}
+
+// resultLabel returns the name of the test, annotated with its status if it didn't pass
+func resultLabel(result tester.Result) string {
+	if result.Status == "" || result.Status == tester.StatusPass {
+		return result.Name
+	}
+	return fmt.Sprintf("%s (%s)", result.Name, result.Status)
+}
+
+type resultStatus struct {
+	Name      string        `json:"name"`
+	Status    tester.Status `json:"status"`
+	SetupHits int           `json:"setup_hits,omitempty"`
+}
+
+// printStatuses prints the results in order along with their status
+func printStatuses(dst io.Writer, results []tester.Result, jsonFmt bool) error {
+	if jsonFmt {
+		out := make([]resultStatus, len(results))
+		for i := range results {
+			out[i] = resultStatus{Name: results[i].Name, Status: results[i].Status, SetupHits: results[i].SetupHits}
+		}
+		b, err := json.Marshal(out)
+		if err != nil {
+			return err
+		}
+		_, err = dst.Write(b)
+		return err
+	}
+
+	labels := make([]string, len(results))
+	for i := range results {
+		labels[i] = resultLabel(results[i])
+	}
+	return printTests(dst, labels, false)
+}
+
+type resultMetrics struct {
+	Name string `json:"name"`
+	metrics
+}
+
+// printResults prints the results in order along with their metrics
+func printResults(dst io.Writer, results []tester.Result, jsonFmt bool) error {
+	if jsonFmt {
+		out := make([]resultMetrics, len(results))
+		for i := range results {
+			out[i] = resultMetrics{Name: results[i].Name, metrics: newMetrics(results[i])}
+		}
+		b, err := json.Marshal(out)
+		if err != nil {
+			return err
+		}
+		_, err = dst.Write(b)
+		return err
+	}
+
+	w := tabwriter.NewWriter(dst, 0, 0, 2, ' ', 0)
+	for i := range results {
+		m := newMetrics(results[i])
+		if _, err := fmt.Fprintf(w, "%s\t%s\t%d stmts\t%d hits\n", resultLabel(results[i]), m.Elapsed, m.Statements, m.Hits); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+type stability struct {
+	Stable   []string         `json:"stable"`   // tests which covered the position in every run
+	Unstable []unstableResult `json:"unstable"` // tests which covered the position in only some runs
+}
+
+type unstableResult struct {
+	Name        string `json:"name"`
+	CoveredRuns int    `json:"covered_runs"`
+	Runs        int    `json:"runs"`
+}
+
+// printStability prints the results in order, marking those which covered the position in only some runs
+func printStability(dst io.Writer, results []tester.Result, jsonFmt bool) error {
+	if jsonFmt {
+		out := stability{Stable: []string{}, Unstable: []unstableResult{}}
+		for _, r := range results {
+			if r.Unstable() {
+				out.Unstable = append(out.Unstable, unstableResult{Name: r.Name, CoveredRuns: r.CoveredRuns, Runs: r.Runs})
+			} else {
+				out.Stable = append(out.Stable, r.Name)
+			}
+		}
+		b, err := json.Marshal(out)
+		if err != nil {
+			return err
+		}
+		_, err = dst.Write(b)
+		return err
+	}
+
+	for _, r := range results {
+		line := resultLabel(r)
+		if r.Unstable() {
+			line = fmt.Sprintf("%s (unstable: covered in %d/%d runs)", line, r.CoveredRuns, r.Runs)
+		}
+		if _, err := fmt.Fprintf(dst, "%s\n", line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// printPackageTests prints the tests of multiple packages, each line of the form '<import path> <test>'
+// tests are printed in the order provided
+func printPackageTests(dst io.Writer, byPackage map[string][]string, jsonFmt bool) error {
+	if jsonFmt {
+		b, err := json.Marshal(byPackage)
+		if err != nil {
+			return err
+		}
+		_, err = dst.Write(b)
+		return err
+	}
+
+	pkgs := make([]string, 0, len(byPackage))
+	for pkg := range byPackage {
+		pkgs = append(pkgs, pkg)
+	}
+	sort.Strings(pkgs)
+	for _, pkg := range pkgs {
+		for _, test := range byPackage[pkg] {
+			if _, err := fmt.Fprintf(dst, "%s %s\n", pkg, test); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+type runFlags struct {
+	Run   string `json:"run"`
+	Skip  string `json:"skip,omitempty"`
+	Bench string `json:"bench,omitempty"`
+}
+
+// printRunFlags prints the '-run', '-skip', and '-bench' arguments to 'go test', quoted for use in a shell
+func printRunFlags(dst io.Writer, run, skip, bench string, jsonFmt bool) error {
+	if jsonFmt {
+		b, err := json.Marshal(runFlags{Run: run, Skip: skip, Bench: bench})
+		if err != nil {
+			return err
+		}
+		_, err = dst.Write(b)
+		return err
+	}
+
+	line := "-run " + shellQuote(run)
+	if skip != "" {
+		line += " -skip " + shellQuote(skip)
+	}
+	if bench != "" {
+		line += " -bench " + shellQuote(bench)
+	}
+	_, err := fmt.Fprintf(dst, "%s\n", line)
+	return err
+}
+
+// shellQuote quotes s in single quotes for a POSIX shell
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
 type testPosition struct {
 	finder.TestPosition
-	SubTests []string `json:"subtests,omitempty"`
+	SubTests  []string      `json:"subtests,omitempty"`
+	Status    tester.Status `json:"status,omitempty"`
+	SetupHits int           `json:"setup_hits,omitempty"` // times package initialization or TestMain executed the position
+	metrics   metrics
 }
 
 func printCoveringPostions(dst io.Writer, positions map[string]*testPosition, positionTests []string, jsonFmt bool, lineFmt string) error {
@@ -57,6 +250,10 @@ func fmtPosition(pos testPosition, testName, fmt string) string {
 	line = strings.ReplaceAll(line, "%c", strconv.Itoa(pos.Col))
 	line = strings.ReplaceAll(line, "%o", strconv.Itoa(pos.Offset))
 	line = strings.ReplaceAll(line, "%s", strings.Join(pos.SubTests, ","))
+	line = strings.ReplaceAll(line, "%d", pos.metrics.Elapsed)
+	line = strings.ReplaceAll(line, "%n", strconv.Itoa(pos.metrics.Statements))
+	line = strings.ReplaceAll(line, "%h", strconv.Itoa(pos.metrics.Hits))
+	line = strings.ReplaceAll(line, "%S", string(pos.Status))
 
 	return line
 }
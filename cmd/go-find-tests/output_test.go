@@ -1,9 +1,13 @@
 package main
 
 import (
+	"bytes"
 	"testing"
+	"time"
 
+	"github.com/ShawnROGrady/go-find-tests/cover"
 	"github.com/ShawnROGrady/go-find-tests/finder"
+	"github.com/ShawnROGrady/go-find-tests/tester"
 )
 
 var (
@@ -16,6 +20,8 @@ var (
 			Offset: 1580,
 		},
 		SubTests: []string{"TestPackageTests/10_tests_1_file", "TestPackageTests/20_tests_2_files"},
+		Status:   tester.StatusPass,
+		metrics:  metrics{Elapsed: "10ms", Statements: 42, Hits: 3},
 	}
 )
 
@@ -23,6 +29,8 @@ var fmtPositionTests = map[string]string{
 	"%t:%f:%l:%c":    "TestPackageTests:finder/finder_test.go:79:1",
 	"%f:%t":          "finder/finder_test.go:TestPackageTests",
 	"%t:%f:%l:%c:%s": "TestPackageTests:finder/finder_test.go:79:1:TestPackageTests/10_tests_1_file,TestPackageTests/20_tests_2_files",
+	"%t %d %n %h":    "TestPackageTests 10ms 42 3",
+	"%t %S":          "TestPackageTests pass",
 }
 
 func TestFmtPosition(t *testing.T) {
@@ -35,3 +43,143 @@ func TestFmtPosition(t *testing.T) {
 		})
 	}
 }
+
+var printResultsTests = map[string]struct {
+	jsonFmt        bool
+	expectedOutput string
+}{
+	"plain": {
+		jsonFmt:        false,
+		expectedOutput: "TestFast           1ms   10 stmts   4 hits\nTestSlower (fail)  1.5s  200 stmts  1 hits\n",
+	},
+	"json": {
+		jsonFmt:        true,
+		expectedOutput: `[{"name":"TestFast","elapsed":"1ms","statements":10,"hits":4,"status":"pass"},{"name":"TestSlower","elapsed":"1.5s","statements":200,"hits":1,"setup_hits":2,"status":"fail"}]`,
+	},
+}
+
+func TestPrintResults(t *testing.T) {
+	results := []tester.Result{
+		{Name: "TestFast", Elapsed: time.Millisecond, Statements: 10, Block: cover.Block{Count: 4}, Status: tester.StatusPass},
+		{Name: "TestSlower", Elapsed: 1500 * time.Millisecond, Statements: 200, Block: cover.Block{Count: 1}, SetupHits: 2, Status: tester.StatusFail},
+	}
+	for testName, test := range printResultsTests {
+		t.Run(testName, func(t *testing.T) {
+			var b bytes.Buffer
+			if err := printResults(&b, results, test.jsonFmt); err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if output := b.String(); output != test.expectedOutput {
+				t.Errorf("Unexpected output (expected = '%s', actual = '%s')", test.expectedOutput, output)
+			}
+		})
+	}
+}
+
+var printStatusesTests = map[string]struct {
+	jsonFmt        bool
+	expectedOutput string
+}{
+	"text": {
+		expectedOutput: "TestPass\nTestFail (fail)\nTestSkip (skip)\n",
+	},
+	"json": {
+		jsonFmt:        true,
+		expectedOutput: `[{"name":"TestPass","status":"pass"},{"name":"TestFail","status":"fail","setup_hits":1},{"name":"TestSkip","status":"skip"}]`,
+	},
+}
+
+func TestPrintStatuses(t *testing.T) {
+	results := []tester.Result{
+		{Name: "TestPass", Status: tester.StatusPass},
+		{Name: "TestFail", Status: tester.StatusFail, SetupHits: 1},
+		{Name: "TestSkip", Status: tester.StatusSkip},
+	}
+	for testName, test := range printStatusesTests {
+		t.Run(testName, func(t *testing.T) {
+			var b bytes.Buffer
+			if err := printStatuses(&b, results, test.jsonFmt); err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if output := b.String(); output != test.expectedOutput {
+				t.Errorf("Unexpected output (expected = '%s', actual = '%s')", test.expectedOutput, output)
+			}
+		})
+	}
+}
+
+var printStabilityTests = map[string]struct {
+	jsonFmt        bool
+	expectedOutput string
+}{
+	"text": {
+		expectedOutput: "TestStable\nTestFlaky (unstable: covered in 2/5 runs)\n",
+	},
+	"json": {
+		jsonFmt:        true,
+		expectedOutput: `{"stable":["TestStable"],"unstable":[{"name":"TestFlaky","covered_runs":2,"runs":5}]}`,
+	},
+}
+
+func TestPrintStability(t *testing.T) {
+	results := []tester.Result{
+		{Name: "TestStable", Runs: 5, CoveredRuns: 5},
+		{Name: "TestFlaky", Runs: 5, CoveredRuns: 2},
+	}
+	for testName, test := range printStabilityTests {
+		t.Run(testName, func(t *testing.T) {
+			var b bytes.Buffer
+			if err := printStability(&b, results, test.jsonFmt); err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if output := b.String(); output != test.expectedOutput {
+				t.Errorf("Unexpected output (expected = '%s', actual = '%s')", test.expectedOutput, output)
+			}
+		})
+	}
+}
+
+var printRunFlagsTests = map[string]struct {
+	run, skip, bench string
+	jsonFmt          bool
+	expectedOutput   string
+}{
+	"run_only": {
+		run:            "^TestA$|^TestB$",
+		expectedOutput: "-run '^TestA$|^TestB$'\n",
+	},
+	"run_and_skip": {
+		run:            "^TestA$",
+		skip:           "^TestA$/^long_input$",
+		expectedOutput: "-run '^TestA$' -skip '^TestA$/^long_input$'\n",
+	},
+	"quote_in_name": {
+		run:            "^TestA$/^it's$",
+		expectedOutput: `-run '^TestA$/^it'\''s$'` + "\n",
+	},
+	"bench": {
+		run:            "^$",
+		bench:          "^BenchmarkF$/^small$",
+		expectedOutput: "-run '^$' -bench '^BenchmarkF$/^small$'\n",
+	},
+	"json": {
+		run:            "^TestA$",
+		skip:           "^TestA$/^long_input$",
+		jsonFmt:        true,
+		expectedOutput: `{"run":"^TestA$","skip":"^TestA$/^long_input$"}`,
+	},
+}
+
+func TestPrintRunFlags(t *testing.T) {
+	for testName, test := range printRunFlagsTests {
+		t.Run(testName, func(t *testing.T) {
+			var b bytes.Buffer
+			if err := printRunFlags(&b, test.run, test.skip, test.bench, test.jsonFmt); err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if output := b.String(); output != test.expectedOutput {
+				t.Errorf("Unexpected output (expected = '%s', actual = '%s')", test.expectedOutput, output)
+			}
+		})
+	}
+}
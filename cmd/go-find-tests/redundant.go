@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/ShawnROGrady/go-find-tests/tester"
+)
+
+var redundantCommand = command{
+	usage:       "package",
+	description: "prints the tests of the package whose coverage is retained by other tests, including duplicate subtests with -include-subs, from the slowest",
+	flags: func(fs *flag.FlagSet) func(args []string, jsonFmt bool, dst io.Writer) error {
+		testerFlags := addTesterFlags(fs)
+		includeSubtests := fs.Bool("include-subs", false, "Also run each subtest on its own, reporting subtests which duplicate others, e.g. cases of table tests")
+		return func(args []string, jsonFmt bool, dst io.Writer) error {
+			if len(args) != 1 {
+				return &usageErr{msg: "Package argument required"}
+			}
+			conf := testerFlags.config()
+			conf.IncludeSubtests = *includeSubtests
+			return redundant(conf, args[0], jsonFmt, dst)
+		}
+	},
+}
+
+func redundant(conf tester.Config, pkg string, jsonFmt bool, dst io.Writer) error {
+	t, err := tester.NewPackage(pkg, conf)
+	if err != nil {
+		return fmt.Errorf("Error constructing tester: %w", err)
+	}
+
+	profiles, err := t.Profiles()
+	if err != nil {
+		return fmt.Errorf("Error collecting test profiles: %w", err)
+	}
+
+	if err := printRedundant(dst, tester.Redundant(profiles), suiteElapsed(profiles), jsonFmt); err != nil {
+		return fmt.Errorf("Error writing output: %w", err)
+	}
+	return nil
+}
+
+// suiteElapsed returns the total elapsed time of the top-level tests, which includes that of their subtests
+func suiteElapsed(profiles []tester.TestProfile) time.Duration {
+	var elapsed time.Duration
+	for i := range profiles {
+		if !strings.Contains(profiles[i].Name, "/") {
+			elapsed += profiles[i].Elapsed
+		}
+	}
+	return elapsed
+}
+
+type redundantTest struct {
+	Name       string   `json:"name"`
+	Elapsed    string   `json:"elapsed"`
+	Statements int      `json:"statements"`
+	CoveredBy  []string `json:"covered_by"`
+	Duplicate  bool     `json:"duplicate"`
+}
+
+type redundantOutput struct {
+	Tests        []redundantTest `json:"tests"`
+	Saved        string          `json:"saved"`
+	SuiteElapsed string          `json:"suite_elapsed"`
+}
+
+func printRedundant(dst io.Writer, redundant []tester.RedundantTest, suite time.Duration, jsonFmt bool) error {
+	var saved time.Duration
+	for i := range redundant {
+		saved += redundant[i].Test.Elapsed
+	}
+
+	if jsonFmt {
+		out := redundantOutput{Tests: make([]redundantTest, len(redundant)), Saved: saved.String(), SuiteElapsed: suite.String()}
+		for i, r := range redundant {
+			out.Tests[i] = redundantTest{
+				Name:       r.Test.Name,
+				Elapsed:    r.Test.Elapsed.String(),
+				Statements: r.Statements,
+				CoveredBy:  r.CoveredBy,
+				Duplicate:  r.Duplicate,
+			}
+		}
+		b, err := json.Marshal(out)
+		if err != nil {
+			return err
+		}
+		_, err = dst.Write(b)
+		return err
+	}
+
+	for _, r := range redundant {
+		if _, err := fmt.Fprintf(dst, "%s (%s, %d statements): %s\n", r.Test.Name, r.Test.Elapsed, r.Statements, redundantReason(r)); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(dst, "\nredundant: %d tests\ntime: %s of %s\n", len(redundant), saved, suite)
+	return err
+}
+
+// redundantReason describes the tests which retain the coverage of a redundant test
+func redundantReason(r tester.RedundantTest) string {
+	switch {
+	case len(r.CoveredBy) == 0:
+		return "covers no statements"
+	case r.Duplicate:
+		return "duplicate of " + r.CoveredBy[0]
+	}
+	return "covered by " + strings.Join(r.CoveredBy, ", ")
+}
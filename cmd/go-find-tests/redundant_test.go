@@ -0,0 +1,53 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/ShawnROGrady/go-find-tests/tester"
+)
+
+var printRedundantTests = map[string]struct {
+	jsonFmt        bool
+	expectedOutput string
+}{
+	"plain": {
+		jsonFmt:        false,
+		expectedOutput: "TestSlow (1s, 10 statements): covered by TestA, TestB\nTestTable/b (20ms, 3 statements): duplicate of TestTable/a\nTestNothing (10ms, 0 statements): covers no statements\n\nredundant: 3 tests\ntime: 1.03s of 2s\n",
+	},
+	"json": {
+		jsonFmt:        true,
+		expectedOutput: `{"tests":[{"name":"TestSlow","elapsed":"1s","statements":10,"covered_by":["TestA","TestB"],"duplicate":false},{"name":"TestTable/b","elapsed":"20ms","statements":3,"covered_by":["TestTable/a"],"duplicate":true},{"name":"TestNothing","elapsed":"10ms","statements":0,"covered_by":[],"duplicate":false}],"saved":"1.03s","suite_elapsed":"2s"}`,
+	},
+}
+
+func TestPrintRedundant(t *testing.T) {
+	redundant := []tester.RedundantTest{
+		{Test: tester.TestProfile{Name: "TestSlow", Elapsed: time.Second}, Statements: 10, CoveredBy: []string{"TestA", "TestB"}},
+		{Test: tester.TestProfile{Name: "TestTable/b", Elapsed: 20 * time.Millisecond}, Statements: 3, CoveredBy: []string{"TestTable/a"}, Duplicate: true},
+		{Test: tester.TestProfile{Name: "TestNothing", Elapsed: 10 * time.Millisecond}, CoveredBy: []string{}},
+	}
+	for testName, test := range printRedundantTests {
+		t.Run(testName, func(t *testing.T) {
+			var b bytes.Buffer
+			if err := printRedundant(&b, redundant, 2*time.Second, test.jsonFmt); err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if output := b.String(); output != test.expectedOutput {
+				t.Errorf("Unexpected output (expected = '%s', actual = '%s')", test.expectedOutput, output)
+			}
+		})
+	}
+}
+
+func TestSuiteElapsed(t *testing.T) {
+	profiles := []tester.TestProfile{
+		{Name: "TestA", Elapsed: time.Second},
+		{Name: "TestA/b", Elapsed: 900 * time.Millisecond},
+		{Name: "TestC", Elapsed: 10 * time.Millisecond},
+	}
+	if elapsed := suiteElapsed(profiles); elapsed != 1010*time.Millisecond {
+		t.Errorf("Unexpected suite elapsed time (expected = 1.01s, actual = %s)", elapsed)
+	}
+}
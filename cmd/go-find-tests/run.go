@@ -1,13 +1,16 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"os"
+	"os/signal"
 	"path/filepath"
-	"sort"
 	"strings"
+	"time"
 
-	"github.com/ShawnROGrady/go-find-tests/finder"
+	"github.com/ShawnROGrady/go-find-tests/cover"
 	"github.com/ShawnROGrady/go-find-tests/tester"
 )
 
@@ -16,90 +19,231 @@ type runConfig struct {
 	jsonFmt        bool
 	lineFmt        string
 	printPositions bool
+	sortOrder      tester.SortOrder // if set, metrics are shown along with each result
+	exec           bool             // run the covering tests instead of printing them
+	goTestArgs     []string         // with exec: additional arguments to 'go test'
+	runPattern     bool             // print the arguments to 'go test' which select the covering tests
+	watch          bool             // print updated results each time the source of the package changes
+	watchInterval  time.Duration    // with watch: how often the source is polled
+}
+
+// includesStatus returns whether failing or skipped tests may be reported, so the status of each result is printed
+func (c runConfig) includesStatus() bool {
+	return c.testerConf.IncludeFailing || c.testerConf.IncludeSkipped
 }
 
 func run(conf runConfig, path string, line, col int, dst io.Writer) error {
 	t, err := tester.New(path, line, col, conf.testerConf)
 	if err != nil {
-		return fmt.Errorf("Error constructing tester: %s", err)
+		return fmt.Errorf("Error constructing tester: %w", err)
+	}
+
+	if conf.watch {
+		return watch(t, conf, path, dst)
 	}
 
-	coveredBy, err := t.CoveredBy()
+	results, err := t.Results()
 	if err != nil {
-		return fmt.Errorf("Error determining covering tests: %s", err)
+		return fmt.Errorf("Error determining covering tests: %w", err)
 	}
-	sort.Slice(coveredBy, func(i, j int) bool { return coveredBy[i] < coveredBy[j] })
+	return printRun(t, conf, path, results, dst)
+}
 
-	if !conf.printPositions {
-		if err := printTests(dst, coveredBy, conf.jsonFmt); err != nil {
-			return fmt.Errorf("Error writing output: %s", err)
+// watch prints the covering tests each time the source of the package changes until interrupted
+func watch(t *tester.Tester, conf runConfig, path string, dst io.Writer) error {
+	if conf.exec || conf.testerConf.WorkspaceDependents {
+		return &usageErr{msg: "-watch cannot be combined with -exec or -work-deps"}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+	go func() {
+		<-interrupt
+		cancel()
+	}()
+
+	var printErr error
+	err := t.Watch(ctx, conf.watchInterval, func(results []tester.Result, err error) {
+		if err != nil {
+			// errors while watching are expected while files are being edited
+			printError(os.Stderr, fmt.Errorf("Error determining covering tests: %w", err), conf.jsonFmt)
+			return
+		}
+		if !conf.jsonFmt {
+			fmt.Fprintf(dst, "--- %s ---\n", time.Now().Format("15:04:05"))
+		}
+		if err := printRun(t, conf, path, results, dst); err != nil {
+			printErr = err
+			cancel()
+			return
+		}
+		if conf.jsonFmt {
+			// one json document per line
+			fmt.Fprint(dst, "\n")
+		}
+	})
+	if printErr != nil {
+		return printErr
+	}
+	return err
+}
+
+// printRun prints the results in the format specified by conf
+func printRun(t *tester.Tester, conf runConfig, path string, results []tester.Result, dst io.Writer) error {
+	if block, ok := t.CoveredBySetup(); ok {
+		fmt.Fprint(os.Stderr, setupNote(block))
+	}
+	if conf.sortOrder != "" {
+		tester.Sort(results, conf.sortOrder)
+	} else {
+		tester.Sort(results, tester.SortName)
+	}
+
+	if conf.exec {
+		return execResults(t, results, conf, dst)
+	}
+
+	if conf.runPattern {
+		if conf.printPositions || conf.testerConf.WorkspaceDependents {
+			return &usageErr{msg: "-run-pattern cannot be combined with -print-positions or -work-deps"}
+		}
+		run, skip, bench := tester.RunFlags(results)
+		if err := printRunFlags(dst, run, skip, bench, conf.jsonFmt); err != nil {
+			return fmt.Errorf("Error writing output: %w", err)
 		}
 		return nil
 	}
 
-	dir, _ := filepath.Split(path)
-	allPositions, err := finder.PackageTests(dir)
-	if err != nil {
-		return fmt.Errorf("Error finding tests in %s: %s", dir, err)
-	}
-
-	var (
-		coveringPositions map[string]*testPosition
-		positionTests     []string
-	)
-
-	if !conf.testerConf.IncludeSubtests {
-		coveringPositions = make(map[string]*testPosition)
-		positionTests = []string{}
-		for i := range coveredBy {
-			if pos, ok := allPositions[coveredBy[i]]; ok {
-				coveringPositions[coveredBy[i]] = &testPosition{TestPosition: pos}
-				// keep track of tests with positions to ensure consistent order when printing
-				positionTests = append(positionTests, coveredBy[i])
+	if conf.testerConf.WorkspaceDependents {
+		if conf.printPositions {
+			return &usageErr{msg: "-print-positions cannot be combined with -work-deps"}
+		}
+		byPackage := map[string][]string{t.Package(): {}}
+		for i := range results {
+			byPackage[results[i].Package] = append(byPackage[results[i].Package], results[i].Name)
+		}
+		if err := printPackageTests(dst, byPackage, conf.jsonFmt); err != nil {
+			return fmt.Errorf("Error writing output: %w", err)
+		}
+		return nil
+	}
+
+	if !conf.printPositions {
+		if conf.testerConf.Runs > 1 {
+			if err := printStability(dst, results, conf.jsonFmt); err != nil {
+				return fmt.Errorf("Error writing output: %w", err)
 			}
+			return nil
 		}
-	} else {
-		coveringPositions, positionTests = positionSubs(allPositions, coveredBy)
+		if conf.sortOrder != "" {
+			if err := printResults(dst, results, conf.jsonFmt); err != nil {
+				return fmt.Errorf("Error writing output: %w", err)
+			}
+			return nil
+		}
+		if conf.includesStatus() {
+			if err := printStatuses(dst, results, conf.jsonFmt); err != nil {
+				return fmt.Errorf("Error writing output: %w", err)
+			}
+			return nil
+		}
+		coveredBy := make([]string, len(results))
+		for i := range results {
+			coveredBy[i] = results[i].Name
+		}
+		if err := printTests(dst, coveredBy, conf.jsonFmt); err != nil {
+			return fmt.Errorf("Error writing output: %w", err)
+		}
+		return nil
 	}
+
+	coveringPositions, positionTests := resultPositions(results, positionsDir(path, t.Dir()))
 	if err := printCoveringPostions(dst, coveringPositions, positionTests, conf.jsonFmt, conf.lineFmt); err != nil {
-		return fmt.Errorf("Error writing output: %s", err)
+		return fmt.Errorf("Error writing output: %w", err)
 	}
 
 	return nil
 }
 
-func positionSubs(allPositions map[string]finder.TestPosition, coveredBy []string) (map[string]*testPosition, []string) {
+// execResults runs the covering tests, streaming their output to dst
+func execResults(t *tester.Tester, results []tester.Result, conf runConfig, dst io.Writer) error {
+	if conf.printPositions {
+		return &usageErr{msg: "-print-positions cannot be combined with -exec"}
+	}
+	if len(results) == 0 {
+		fmt.Fprintln(os.Stderr, "No covering tests found")
+		return nil
+	}
+
+	args := conf.goTestArgs
+	if len(args) != 0 && args[0] == "--" {
+		args = args[1:]
+	}
+	if conf.jsonFmt {
+		args = append([]string{"-json"}, args...)
+	}
+	if err := t.Exec(results, dst, os.Stderr, args...); err != nil {
+		return fmt.Errorf("Error running covering tests: %w", err)
+	}
+	return nil
+}
+
+// positionsDir returns the directory used when reporting test positions
+// the directory of the provided path is preferred if it exists so that positions are reported relative to it
+func positionsDir(path, pkgDir string) string {
+	dir, _ := filepath.Split(path)
+	if dir == "" {
+		return "."
+	}
+	if info, err := os.Stat(dir); err == nil && info.IsDir() {
+		return dir
+	}
+	return pkgDir
+}
+
+// resultPositions returns the positions of the top-level tests of the results, with subtests grouped under them
+// positions are reported relative to dir
+func resultPositions(results []tester.Result, dir string) (map[string]*testPosition, []string) {
 	positions := make(map[string]*testPosition)
 	positionTests := []string{}
 
-	for i := range coveredBy {
-		if pos, ok := allPositions[coveredBy[i]]; ok {
-			// top level test
-			positionTests = append(positionTests, coveredBy[i]) // keep track of tests with positions to ensure consistent order when printing
-			if posInfo, ok := positions[coveredBy[i]]; ok {
-				posInfo.TestPosition = pos
-			} else {
-				positions[coveredBy[i]] = &testPosition{
-					TestPosition: pos,
-				}
+	for i := range results {
+		if results[i].Parent == "" {
+			if results[i].Position == nil {
+				continue
+			}
+			pos := *results[i].Position
+			pos.File = filepath.Join(dir, filepath.Base(pos.File))
+			// keep track of tests with positions to ensure consistent order when printing
+			positionTests = append(positionTests, results[i].Name)
+			posInfo, ok := positions[results[i].Name]
+			if !ok {
+				posInfo = &testPosition{}
+				positions[results[i].Name] = posInfo
 			}
+			posInfo.TestPosition = pos
+			posInfo.metrics = newMetrics(results[i])
+			posInfo.Status = results[i].Status
+			posInfo.SetupHits = results[i].SetupHits
 			continue
 		}
 
-		if parts := strings.Split(coveredBy[i], "/"); len(parts) >= 2 {
-			if posInfo, ok := positions[parts[0]]; ok {
-				if len(posInfo.SubTests) == 0 {
-					posInfo.SubTests = []string{coveredBy[i]}
-				} else {
-					posInfo.SubTests = append(posInfo.SubTests, coveredBy[i])
-				}
-			} else {
-				positions[parts[0]] = &testPosition{
-					SubTests: []string{coveredBy[i]},
-				}
-			}
+		top := strings.SplitN(results[i].Name, "/", 2)[0]
+		if posInfo, ok := positions[top]; ok {
+			posInfo.SubTests = append(posInfo.SubTests, results[i].Name)
+		} else {
+			positions[top] = &testPosition{SubTests: []string{results[i].Name}}
 		}
 	}
 
 	return positions, positionTests
 }
+
+// setupNote explains that the position is covered by package initialization or TestMain, and how that affects the results
+// it is only known when that coverage is excluded, see tester.Tester.CoveredBySetup
+func setupNote(block cover.Block) string {
+	return fmt.Sprintf("note: %s:%d is covered %d time(s) by package initialization or TestMain, only tests which cover it more often are reported (see -include-setup)\n", block.File, block.StartLine, block.Count)
+}
@@ -2,16 +2,13 @@ package main
 
 import (
 	"bytes"
+	"path/filepath"
 	"testing"
 
+	"github.com/ShawnROGrady/go-find-tests/cover"
 	"github.com/ShawnROGrady/go-find-tests/tester"
 )
 
-/*
-NOTE: since tester.New() doesn't play well with testdata directory
-these tests all check coverage of actual implementation. Since this
-is likely to change these test will need to be updated accordingly
-*/
 var runTests = map[string]struct {
 	conf           runConfig
 	path           string
@@ -20,36 +17,86 @@ var runTests = map[string]struct {
 	expectErr      bool
 	expectedOutput string
 }{
+	"json_covered_by_setup": {
+		conf: runConfig{
+			jsonFmt:        true,
+			printPositions: true,
+			lineFmt:        defaultLineFmt,
+		},
+		path: "../../testdata/setup/setup.go",
+		line: 6, col: 0, // initializer of a package level variable, rerun by TestRebuild
+		expectedOutput: `{"TestRebuild":{"file":"../../testdata/setup/setup_test.go","line":17,"col":1,"offset":156,"status":"pass","setup_hits":1}}`,
+	},
 	"default_options": {
 		conf: runConfig{
 			lineFmt: defaultLineFmt,
 		},
-		path: "../../cover/profile.go",
-		line: 154, col: 12, // success case parseLine()
+		path: "../../testdata/subtests/len.go",
+		line: 9, col: 0, // "empty" case of length()
+		expectErr:      false,
+		expectedOutput: "TestIsEmpty\nTestIsShort\n",
+	},
+	"absolute_path": {
+		conf: runConfig{
+			lineFmt: defaultLineFmt,
+		},
+		path: absPath("../../testdata/subtests/len.go"),
+		line: 9, col: 0, // "empty" case of length()
+		expectErr:      false,
+		expectedOutput: "TestIsEmpty\nTestIsShort\n",
+	},
+	"module_relative_path": {
+		conf: runConfig{
+			lineFmt: defaultLineFmt,
+		},
+		path: "testdata/subtests/len.go",
+		line: 9, col: 0, // "empty" case of length()
+		expectErr:      false,
+		expectedOutput: "TestIsEmpty\nTestIsShort\n",
+	},
+	"import_path_qualified": {
+		conf: runConfig{
+			lineFmt: defaultLineFmt,
+		},
+		path: "github.com/ShawnROGrady/go-find-tests/testdata/subtests/len.go",
+		line: 9, col: 0, // "empty" case of length()
 		expectErr:      false,
-		expectedOutput: "TestCovers\nTestParseLine\n",
+		expectedOutput: "TestIsEmpty\nTestIsShort\n",
 	},
 	"run_filter_set": {
 		conf: runConfig{
 			lineFmt: defaultLineFmt,
 			testerConf: tester.Config{
-				Run: "TestCovers",
+				Run: "TestIsEmpty",
+			},
+		},
+		path: "../../testdata/subtests/len.go",
+		line: 9, col: 0, // "empty" case of length()
+		expectErr:      false,
+		expectedOutput: "TestIsEmpty\n",
+	},
+	"run_pattern": {
+		conf: runConfig{
+			lineFmt:    defaultLineFmt,
+			runPattern: true,
+			testerConf: tester.Config{
+				IncludeSubtests: true,
 			},
 		},
-		path: "../../cover/profile.go",
-		line: 154, col: 12, // success case parseLine()
+		path: "../../testdata/subtests/len.go",
+		line: 9, col: 0, // "empty" case of length()
 		expectErr:      false,
-		expectedOutput: "TestCovers\n",
+		expectedOutput: "-run '^TestIsEmpty$/^empty_input$|^TestIsShort$/^empty_input$'\n",
 	},
 	"json_printing_no_subs": {
 		conf: runConfig{
 			lineFmt: defaultLineFmt,
 			jsonFmt: true,
 		},
-		path: "../../cover/profile.go",
-		line: 154, col: 12, // success case parseLine()
+		path: "../../testdata/subtests/len.go",
+		line: 9, col: 0, // "empty" case of length()
 		expectErr:      false,
-		expectedOutput: `["TestCovers","TestParseLine"]`,
+		expectedOutput: `["TestIsEmpty","TestIsShort"]`,
 	},
 	"subs_enabled": {
 		conf: runConfig{
@@ -58,10 +105,10 @@ var runTests = map[string]struct {
 				IncludeSubtests: true,
 			},
 		},
-		path: "../../cover/profile.go",
-		line: 154, col: 12, // success case parseLine()
+		path: "../../testdata/subtests/len.go",
+		line: 9, col: 0, // "empty" case of length()
 		expectErr:      false,
-		expectedOutput: "TestCovers\nTestCovers/end_of_coverage\nTestCovers/in_uncovered_block\nTestCovers/middle_of_covered_line\nTestCovers/start_of_coverage\nTestCovers/uncovered_file\nTestParseLine\nTestParseLine/covered_line\nTestParseLine/uncovered_line\nTestParseLine/uncovered_line_with_dot_in_file\n",
+		expectedOutput: "TestIsEmpty\nTestIsEmpty/empty_input\nTestIsShort\nTestIsShort/empty_input\n",
 	},
 	"json_printing_subs_enabled": {
 		conf: runConfig{
@@ -71,20 +118,20 @@ var runTests = map[string]struct {
 			},
 			jsonFmt: true,
 		},
-		path: "../../cover/profile.go",
-		line: 154, col: 12, // success case parseLine()
+		path: "../../testdata/subtests/len.go",
+		line: 9, col: 0, // "empty" case of length()
 		expectErr:      false,
-		expectedOutput: `["TestCovers","TestCovers/end_of_coverage","TestCovers/in_uncovered_block","TestCovers/middle_of_covered_line","TestCovers/start_of_coverage","TestCovers/uncovered_file","TestParseLine","TestParseLine/covered_line","TestParseLine/uncovered_line","TestParseLine/uncovered_line_with_dot_in_file"]`,
+		expectedOutput: `["TestIsEmpty","TestIsEmpty/empty_input","TestIsShort","TestIsShort/empty_input"]`,
 	},
 	"with_positions": {
 		conf: runConfig{
 			lineFmt:        defaultLineFmt,
 			printPositions: true,
 		},
-		path: "../../cover/profile.go",
-		line: 154, col: 12, // success case parseLine()
+		path: "../../testdata/subtests/len.go",
+		line: 9, col: 0, // "empty" case of length()
 		expectErr:      false,
-		expectedOutput: "TestCovers:../../cover/profile_test.go:65:1:\nTestParseLine:../../cover/profile_test.go:143:1:\n",
+		expectedOutput: "TestIsEmpty:../../testdata/subtests/len_test.go:23:1:\nTestIsShort:../../testdata/subtests/len_test.go:52:1:\n",
 	},
 	"json_printing_with_positions": {
 		conf: runConfig{
@@ -92,10 +139,10 @@ var runTests = map[string]struct {
 			printPositions: true,
 			jsonFmt:        true,
 		},
-		path: "../../cover/profile.go",
-		line: 154, col: 12, // success case parseLine()
+		path: "../../testdata/subtests/len.go",
+		line: 9, col: 0, // "empty" case of length()
 		expectErr:      false,
-		expectedOutput: `{"TestCovers":{"file":"../../cover/profile_test.go","line":65,"col":1,"offset":1270},"TestParseLine":{"file":"../../cover/profile_test.go","line":143,"col":1,"offset":2928}}`,
+		expectedOutput: `{"TestIsEmpty":{"file":"../../testdata/subtests/len_test.go","line":23,"col":1,"offset":323,"status":"pass"},"TestIsShort":{"file":"../../testdata/subtests/len_test.go","line":52,"col":1,"offset":935,"status":"pass"}}`,
 	},
 	"json_printing_with_positions_and_subs": {
 		conf: runConfig{
@@ -106,10 +153,10 @@ var runTests = map[string]struct {
 				IncludeSubtests: true,
 			},
 		},
-		path: "../../cover/profile.go",
-		line: 154, col: 12, // success case parseLine()
+		path: "../../testdata/subtests/len.go",
+		line: 9, col: 0, // "empty" case of length()
 		expectErr:      false,
-		expectedOutput: `{"TestCovers":{"file":"../../cover/profile_test.go","line":65,"col":1,"offset":1270,"subtests":["TestCovers/end_of_coverage","TestCovers/in_uncovered_block","TestCovers/middle_of_covered_line","TestCovers/start_of_coverage","TestCovers/uncovered_file"]},"TestParseLine":{"file":"../../cover/profile_test.go","line":143,"col":1,"offset":2928,"subtests":["TestParseLine/covered_line","TestParseLine/uncovered_line","TestParseLine/uncovered_line_with_dot_in_file"]}}`,
+		expectedOutput: `{"TestIsEmpty":{"file":"../../testdata/subtests/len_test.go","line":23,"col":1,"offset":323,"subtests":["TestIsEmpty/empty_input"],"status":"pass"},"TestIsShort":{"file":"../../testdata/subtests/len_test.go","line":52,"col":1,"offset":935,"subtests":["TestIsShort/empty_input"],"status":"pass"}}`,
 	},
 	"with_positions_subs_enabled": {
 		conf: runConfig{
@@ -119,13 +166,95 @@ var runTests = map[string]struct {
 				IncludeSubtests: true,
 			},
 		},
-		path: "../../cover/profile.go",
-		line: 154, col: 12, // success case parseLine()
+		path: "../../testdata/subtests/len.go",
+		line: 9, col: 0, // "empty" case of length()
+		expectErr:      false,
+		expectedOutput: "TestIsEmpty:../../testdata/subtests/len_test.go:23:1:TestIsEmpty/empty_input\nTestIsShort:../../testdata/subtests/len_test.go:52:1:TestIsShort/empty_input\n",
+	},
+	"workspace_dependents": {
+		conf: runConfig{
+			lineFmt: defaultLineFmt,
+			testerConf: tester.Config{
+				WorkspaceDependents: true,
+			},
+		},
+		path: "../../testdata/workspace/a/sign.go",
+		line: 8, col: 0, // negative case of Sign()
+		expectErr:      false,
+		expectedOutput: "example.com/b TestAbsNegative\n",
+	},
+	"json_printing_workspace_dependents": {
+		conf: runConfig{
+			lineFmt: defaultLineFmt,
+			jsonFmt: true,
+			testerConf: tester.Config{
+				WorkspaceDependents: true,
+			},
+		},
+		path: "../../testdata/workspace/a/sign.go",
+		line: 8, col: 0, // negative case of Sign()
+		expectErr:      false,
+		expectedOutput: `{"example.com/a":[],"example.com/b":["TestAbsNegative"]}`,
+	},
+	"skip_and_subtest_level": {
+		conf: runConfig{
+			lineFmt: defaultLineFmt,
+			testerConf: tester.Config{
+				IncludeSubtests: true,
+				Run:             "TestParse/^valid_",
+				Skip:            "TestParseAll",
+			},
+		},
+		path: "../../testdata/filter/parse.go",
+		line: 6, col: 0,
+		expectErr:      false,
+		expectedOutput: "TestParse\nTestParse/valid_negative\nTestParse/valid_positive\n",
+	},
+	"failing_test": {
+		conf: runConfig{
+			lineFmt: defaultLineFmt,
+		},
+		path: "../../testdata/status/status.go",
+		line: 4, col: 0,
+		expectErr: true,
+	},
+	"include_failing_and_skipped": {
+		conf: runConfig{
+			lineFmt: defaultLineFmt,
+			testerConf: tester.Config{
+				IncludeFailing: true,
+				IncludeSkipped: true,
+			},
+		},
+		path: "../../testdata/status/status.go",
+		line: 4, col: 0,
 		expectErr:      false,
-		expectedOutput: "TestCovers:../../cover/profile_test.go:65:1:TestCovers/end_of_coverage,TestCovers/in_uncovered_block,TestCovers/middle_of_covered_line,TestCovers/start_of_coverage,TestCovers/uncovered_file\nTestParseLine:../../cover/profile_test.go:143:1:TestParseLine/covered_line,TestParseLine/uncovered_line,TestParseLine/uncovered_line_with_dot_in_file\n",
+		expectedOutput: "TestPasses\nTestSkipped (skip)\nTestStatus (fail)\n",
+	},
+	"json_include_skipped": {
+		conf: runConfig{
+			lineFmt: defaultLineFmt,
+			jsonFmt: true,
+			testerConf: tester.Config{
+				Run:            "TestSkipped|TestPasses",
+				IncludeSkipped: true,
+			},
+		},
+		path: "../../testdata/status/status.go",
+		line: 4, col: 0,
+		expectErr:      false,
+		expectedOutput: `[{"name":"TestPasses","status":"pass"},{"name":"TestSkipped","status":"skip"}]`,
 	},
 }
 
+func absPath(path string) string {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		panic(err)
+	}
+	return abs
+}
+
 func TestRun(t *testing.T) {
 	for testName, testCase := range runTests {
 		t.Run(testName, func(t *testing.T) {
@@ -151,3 +280,11 @@ func TestRun(t *testing.T) {
 		})
 	}
 }
+
+func TestSetupNote(t *testing.T) {
+	block := cover.Block{File: "setup.go", StartLine: 5, Count: 1}
+	expected := "note: setup.go:5 is covered 1 time(s) by package initialization or TestMain, only tests which cover it more often are reported (see -include-setup)\n"
+	if note := setupNote(block); note != expected {
+		t.Errorf("Unexpected note (expected = '%s', actual = '%s')", expected, note)
+	}
+}
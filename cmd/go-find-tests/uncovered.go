@@ -0,0 +1,438 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/ShawnROGrady/go-find-tests/cover"
+	"github.com/ShawnROGrady/go-find-tests/finder"
+	"github.com/ShawnROGrady/go-find-tests/tester"
+)
+
+// formats of the uncovered statement report
+const (
+	uncoveredText   = "text"
+	uncoveredSARIF  = "sarif"
+	uncoveredGitHub = "github"
+	uncoveredGitLab = "gitlab"
+)
+
+var reportUncoveredCommand = command{
+	usage:       "package | -diff file",
+	description: "runs every test of the package, or of each package a diff changes, and reports the statements no test covers grouped by function",
+	flags: func(fs *flag.FlagSet) func(args []string, jsonFmt bool, dst io.Writer) error {
+		var (
+			testerFlags = addTesterFlags(fs)
+			diffFile    = fs.String("diff", "", "Report only statements on lines added by the unified diff in the file, e.g. from 'git diff', or '-' to read it from stdin. Paths are relative to the working directory")
+			format      = fs.String("format", uncoveredText, "The format of the report: 'text', 'sarif' (SARIF 2.1.0), 'github' (workflow command annotations), or 'gitlab' (code quality report)")
+		)
+		return func(args []string, jsonFmt bool, dst io.Writer) error {
+			write, ok := uncoveredWriters[*format]
+			if !ok {
+				return &usageErr{msg: fmt.Sprintf("Unknown report format '%s' (expected one of: %s, %s, %s, %s)", *format, uncoveredText, uncoveredSARIF, uncoveredGitHub, uncoveredGitLab)}
+			}
+			if jsonFmt && *format == uncoveredText {
+				write = writeUncoveredJSON
+			}
+
+			var (
+				dirs  []string
+				added diffLines
+			)
+			switch {
+			case *diffFile != "" && len(args) == 0:
+				var err error
+				if added, err = readDiff(*diffFile); err != nil {
+					return err
+				}
+				for _, dir := range added.packageDirs() {
+					dirs = append(dirs, filepath.FromSlash(dir))
+				}
+			case *diffFile == "" && len(args) == 1:
+				dirs = []string{args[0]}
+			default:
+				return &usageErr{msg: "Either a package argument or -diff is required"}
+			}
+
+			wd, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+			reports := []uncoveredReport{}
+			for _, dir := range dirs {
+				report, err := reportUncovered(dir, wd, added, testerFlags.config())
+				if err != nil {
+					return err
+				}
+				reports = append(reports, report)
+			}
+			if err := write(dst, reports); err != nil {
+				return fmt.Errorf("Error writing output: %w", err)
+			}
+			return nil
+		}
+	},
+}
+
+// readDiff reads the unified diff in the file, or stdin if it is '-'
+func readDiff(name string) (diffLines, error) {
+	r := io.Reader(os.Stdin)
+	if name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+	added, err := parseDiff(r)
+	if err != nil {
+		return nil, &usageErr{msg: fmt.Sprintf("Error parsing diff: %s", err)}
+	}
+	return added, nil
+}
+
+// uncoveredReport is the uncovered statements of a single package
+// file paths are slash separated and relative to the working directory
+type uncoveredReport struct {
+	Package string                 `json:"package"`
+	Funcs   []tester.UncoveredFunc `json:"funcs"`
+
+	sources map[cover.BlockKey]string // source of each uncovered block, which identifies it across commits
+}
+
+// reportUncovered runs the tests of the package identified by pkgPath and reports the statements none of them executed
+// if added is not nil only the statements on the lines it adds are reported
+func reportUncovered(pkgPath, wd string, added diffLines, conf tester.Config) (uncoveredReport, error) {
+	t, err := tester.NewPackage(pkgPath, conf)
+	if err != nil {
+		return uncoveredReport{}, fmt.Errorf("Error constructing tester: %w", err)
+	}
+	profiles, err := t.Profiles()
+	if err != nil {
+		return uncoveredReport{}, fmt.Errorf("Error collecting test profiles: %w", err)
+	}
+	report := uncoveredReport{Package: t.Package(), Funcs: []tester.UncoveredFunc{}, sources: make(map[cover.BlockKey]string)}
+
+	// statements only run by package initialization or TestMain aren't covered by any test, with or without tests
+	setup, err := t.SetupProfile()
+	if err != nil {
+		return uncoveredReport{}, fmt.Errorf("Error running the setup of %s: %w", t.Package(), err)
+	}
+	var m tester.Matrix
+	if len(profiles) == 0 {
+		m = tester.Matrix{Package: t.Package(), Blocks: setup.Blocks()}
+		for i := range m.Blocks {
+			m.Blocks[i].Count = 0
+		}
+	} else {
+		for i := range profiles {
+			profiles[i].Profile = profiles[i].Profile.Subtract(setup)
+		}
+		m = tester.NewMatrix(profiles)
+	}
+	files := []string{}
+	for _, b := range m.Blocks {
+		if len(files) == 0 || files[len(files)-1] != b.File {
+			files = append(files, b.File)
+		}
+	}
+	funcs, err := finder.Funcs(t.Dir(), files)
+	if err != nil {
+		return uncoveredReport{}, fmt.Errorf("Error parsing %s: %w", t.Package(), err)
+	}
+
+	rel, err := filepath.Rel(wd, t.Dir())
+	if err != nil {
+		rel = t.Dir()
+	}
+	rel = filepath.ToSlash(rel)
+	var relevant func(cover.Block) bool
+	if added != nil {
+		relevant = func(b cover.Block) bool {
+			return added.overlaps(path.Join(rel, b.File), b.StartLine, b.EndLine)
+		}
+	}
+
+	lines := make(map[string][]string)
+	for _, u := range tester.Uncovered(m, funcs, relevant) {
+		if _, ok := lines[u.File]; !ok {
+			src, err := ioutil.ReadFile(filepath.Join(t.Dir(), filepath.FromSlash(u.File)))
+			if err != nil {
+				return uncoveredReport{}, fmt.Errorf("Error reading %s: %w", u.File, err)
+			}
+			lines[u.File] = strings.Split(string(src), "\n")
+		}
+		fileLines := lines[u.File]
+
+		u.File = path.Join(rel, u.File)
+		for i := range u.Blocks {
+			u.Blocks[i].File = u.File
+			report.sources[u.Blocks[i].Key()] = blockSource(fileLines, u.Blocks[i])
+		}
+		report.Funcs = append(report.Funcs, u)
+	}
+	return report, nil
+}
+
+// blockSource returns the source of the block from the lines of its file, with runs of whitespace collapsed
+// so that reformatting doesn't change it
+func blockSource(lines []string, b cover.Block) string {
+	if b.StartLine < 1 || b.EndLine > len(lines) || b.StartLine > b.EndLine {
+		return ""
+	}
+	src := make([]string, 0, b.EndLine-b.StartLine+1)
+	for n := b.StartLine; n <= b.EndLine; n++ {
+		line := lines[n-1]
+		if n == b.EndLine && b.EndCol-1 <= len(line) && b.EndCol > 0 {
+			line = line[:b.EndCol-1]
+		}
+		if n == b.StartLine && b.StartCol-1 <= len(line) && b.StartCol > 0 {
+			line = line[b.StartCol-1:]
+		}
+		src = append(src, line)
+	}
+	return strings.Join(strings.Fields(strings.Join(src, "\n")), " ")
+}
+
+var uncoveredWriters = map[string]func(dst io.Writer, reports []uncoveredReport) error{
+	uncoveredText:   writeUncoveredText,
+	uncoveredSARIF:  writeUncoveredSARIF,
+	uncoveredGitHub: writeUncoveredGitHub,
+	uncoveredGitLab: writeUncoveredGitLab,
+}
+
+// funcLabel returns the name of the function for use in messages
+func funcLabel(u tester.UncoveredFunc) string {
+	if u.Name == "" {
+		return "package level declarations"
+	}
+	return u.Name
+}
+
+// uncoveredMessage describes an uncovered block of the function
+func uncoveredMessage(u tester.UncoveredFunc, b cover.Block) string {
+	return fmt.Sprintf("%d statement(s) of %s not covered by any test", b.NumStmt, funcLabel(u))
+}
+
+func writeUncoveredText(dst io.Writer, reports []uncoveredReport) error {
+	for _, report := range reports {
+		for _, u := range report.Funcs {
+			if _, err := fmt.Fprintf(dst, "%s:%d: %s: %d of %d statement(s) uncovered\n", u.File, u.StartLine, funcLabel(u), u.Uncovered, u.Statements); err != nil {
+				return err
+			}
+			for _, b := range u.Blocks {
+				if _, err := fmt.Fprintf(dst, "\t%s:%d.%d,%d.%d\n", b.File, b.StartLine, b.StartCol, b.EndLine, b.EndCol); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func writeUncoveredJSON(dst io.Writer, reports []uncoveredReport) error {
+	b, err := json.Marshal(reports)
+	if err != nil {
+		return err
+	}
+	_, err = dst.Write(b)
+	return err
+}
+
+// sarifLog is the subset of SARIF 2.1.0 used to report uncovered statements
+// see: https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation  `json:"physicalLocation"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           sarifRegion           `json:"region"`
+}
+
+type sarifArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+	EndLine     int `json:"endLine"`
+	EndColumn   int `json:"endColumn"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// uncoveredRule is the id of the rule of every reported result
+const uncoveredRule = "uncovered-statement"
+
+func writeUncoveredSARIF(dst io.Writer, reports []uncoveredReport) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "go-find-tests",
+			InformationURI: "https://github.com/ShawnROGrady/go-find-tests",
+			Rules:          []sarifRule{{ID: uncoveredRule, ShortDescription: sarifMessage{Text: "Statement not covered by any test"}}},
+		}},
+		Results: []sarifResult{},
+	}
+	for _, report := range reports {
+		for _, u := range report.Funcs {
+			var logical []sarifLogicalLocation
+			if u.Name != "" {
+				logical = []sarifLogicalLocation{{FullyQualifiedName: report.Package + "." + u.Name, Kind: "function"}}
+			}
+			for _, b := range u.Blocks {
+				run.Results = append(run.Results, sarifResult{
+					RuleID:  uncoveredRule,
+					Level:   "warning",
+					Message: sarifMessage{Text: uncoveredMessage(u, b)},
+					Locations: []sarifLocation{{
+						PhysicalLocation: sarifPhysicalLocation{
+							// relative to the root of the checkout when run from it, as code scanning expects
+							ArtifactLocation: sarifArtifactLocation{URI: b.File, URIBaseID: "%SRCROOT%"},
+							Region:           sarifRegion{StartLine: b.StartLine, StartColumn: b.StartCol, EndLine: b.EndLine, EndColumn: b.EndCol},
+						},
+						LogicalLocations: logical,
+					}},
+				})
+			}
+		}
+	}
+
+	b, err := json.MarshalIndent(sarifLog{
+		Version: "2.1.0",
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Runs:    []sarifRun{run},
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = dst.Write(append(b, '\n'))
+	return err
+}
+
+// githubEscaper escapes the message of a workflow command, and githubPropertyEscaper the value of one of its properties
+// see: https://docs.github.com/en/actions/using-workflows/workflow-commands-for-github-actions
+var (
+	githubEscaper         = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A")
+	githubPropertyEscaper = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C")
+)
+
+// writeUncoveredGitHub writes a warning workflow command for each uncovered block, which GitHub Actions shows as an annotation
+func writeUncoveredGitHub(dst io.Writer, reports []uncoveredReport) error {
+	for _, report := range reports {
+		for _, u := range report.Funcs {
+			for _, b := range u.Blocks {
+				if _, err := fmt.Fprintf(dst, "::warning file=%s,line=%d,endLine=%d,title=%s::%s\n",
+					githubPropertyEscaper.Replace(b.File), b.StartLine, b.EndLine,
+					githubPropertyEscaper.Replace("Uncovered statements"), githubEscaper.Replace(uncoveredMessage(u, b)),
+				); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// gitlabIssue is an entry of a GitLab code quality report
+// see: https://docs.gitlab.com/ee/ci/testing/code_quality.html#implement-a-custom-tool
+type gitlabIssue struct {
+	Description string         `json:"description"`
+	CheckName   string         `json:"check_name"`
+	Fingerprint string         `json:"fingerprint"`
+	Severity    string         `json:"severity"`
+	Location    gitlabLocation `json:"location"`
+}
+
+type gitlabLocation struct {
+	Path  string      `json:"path"`
+	Lines gitlabLines `json:"lines"`
+}
+
+type gitlabLines struct {
+	Begin int `json:"begin"`
+	End   int `json:"end"`
+}
+
+func writeUncoveredGitLab(dst io.Writer, reports []uncoveredReport) error {
+	issues := []gitlabIssue{}
+	for _, report := range reports {
+		for _, u := range report.Funcs {
+			seen := make(map[string]int)
+			for _, b := range u.Blocks {
+				// the fingerprint identifies the issue across commits, so it includes the source of the block rather than its position,
+				// along with how many identical blocks precede it in the function
+				src := report.sources[b.Key()]
+				sum := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%s\x00%s\x00%s\x00%d", uncoveredRule, b.File, u.Name, src, seen[src])))
+				seen[src]++
+				issues = append(issues, gitlabIssue{
+					Description: uncoveredMessage(u, b),
+					CheckName:   uncoveredRule,
+					Fingerprint: hex.EncodeToString(sum[:16]),
+					Severity:    "minor",
+					Location:    gitlabLocation{Path: b.File, Lines: gitlabLines{Begin: b.StartLine, End: b.EndLine}},
+				})
+			}
+		}
+	}
+
+	b, err := json.Marshal(issues)
+	if err != nil {
+		return err
+	}
+	_, err = dst.Write(b)
+	return err
+}
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/ShawnROGrady/go-find-tests/cover"
+	"github.com/ShawnROGrady/go-find-tests/tester"
+)
+
+var reportUncoveredTests = map[string]struct {
+	pkg           string // default = ../../testdata/uncovered
+	added         diffLines
+	expectedFuncs string
+}{
+	"package": {
+		expectedFuncs: "[../../testdata/uncovered/uncovered.go:3 Abs 1/3 ../../testdata/uncovered/uncovered.go:10 Unused 1/1]",
+	},
+	"diff": {
+		added:         diffLines{"../../testdata/uncovered/uncovered.go": {11: true}},
+		expectedFuncs: "[../../testdata/uncovered/uncovered.go:10 Unused 1/1]",
+	},
+	"diff_of_covered_lines": {
+		added:         diffLines{"../../testdata/uncovered/uncovered.go": {7: true}},
+		expectedFuncs: "[]",
+	},
+	"no_test_files": {
+		// statements run by package initialization are still uncovered by any test
+		pkg:           "../../testdata/notests",
+		expectedFuncs: "[../../testdata/notests/notests.go:5 build 1/1 ../../testdata/notests/notests.go:9 Lookup 3/3]",
+	},
+	"diff_of_no_test_files": {
+		pkg:           "../../testdata/notests",
+		added:         diffLines{"../../testdata/notests/notests.go": {12: true}},
+		expectedFuncs: "[../../testdata/notests/notests.go:9 Lookup 1/3]",
+	},
+	"init_only_statements": {
+		// statements run by package initialization aren't covered by the tests which run after it
+		pkg:           "../../testdata/initonly",
+		expectedFuncs: "[../../testdata/initonly/initonly.go:5 build 1/1]",
+	},
+	"no_tests": {
+		pkg:           "../../testdata/nocases",
+		expectedFuncs: "[../../testdata/nocases/nocases.go:3 Double 1/1]",
+	},
+}
+
+func TestReportUncovered(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	for testName, test := range reportUncoveredTests {
+		test := test
+		t.Run(testName, func(t *testing.T) {
+			pkg := test.pkg
+			if pkg == "" {
+				pkg = "../../testdata/uncovered"
+			}
+			report, err := reportUncovered(pkg, wd, test.added, tester.Config{})
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			actual := []string{}
+			for _, u := range report.Funcs {
+				actual = append(actual, fmt.Sprintf("%s:%d %s %d/%d", u.File, u.StartLine, u.Name, u.Uncovered, u.Statements))
+			}
+			if fmt.Sprint(actual) != test.expectedFuncs {
+				t.Errorf("Unexpected functions (expected = %s, actual = %v)", test.expectedFuncs, actual)
+			}
+		})
+	}
+}
+
+var uncoveredWriterTests = map[string]string{
+	uncoveredText: "pkg/a.go:3: Sum: 2 of 5 statement(s) uncovered\n" +
+		"\tpkg/a.go:5.2,6.3\n" +
+		"pkg/a.go:1: package level declarations: 1 of 1 statement(s) uncovered\n" +
+		"\tpkg/a.go:1.10,1.20\n",
+	uncoveredGitHub: "::warning file=pkg/a.go,line=5,endLine=6,title=Uncovered statements::2 statement(s) of Sum not covered by any test\n" +
+		"::warning file=pkg/a.go,line=1,endLine=1,title=Uncovered statements::1 statement(s) of package level declarations not covered by any test\n",
+	uncoveredGitLab: `[{"description":"2 statement(s) of Sum not covered by any test","check_name":"uncovered-statement","fingerprint":"9c6103d556b273aa52eda8857d8d789b","severity":"minor","location":{"path":"pkg/a.go","lines":{"begin":5,"end":6}}},` +
+		`{"description":"1 statement(s) of package level declarations not covered by any test","check_name":"uncovered-statement","fingerprint":"6c16b0933df29db6f6a323b67eb343d5","severity":"minor","location":{"path":"pkg/a.go","lines":{"begin":1,"end":1}}}]`,
+	uncoveredSARIF: `{
+  "version": "2.1.0",
+  "$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+  "runs": [
+    {
+      "tool": {
+        "driver": {
+          "name": "go-find-tests",
+          "informationUri": "https://github.com/ShawnROGrady/go-find-tests",
+          "rules": [
+            {
+              "id": "uncovered-statement",
+              "shortDescription": {
+                "text": "Statement not covered by any test"
+              }
+            }
+          ]
+        }
+      },
+      "results": [
+        {
+          "ruleId": "uncovered-statement",
+          "level": "warning",
+          "message": {
+            "text": "2 statement(s) of Sum not covered by any test"
+          },
+          "locations": [
+            {
+              "physicalLocation": {
+                "artifactLocation": {
+                  "uri": "pkg/a.go",
+                  "uriBaseId": "%SRCROOT%"
+                },
+                "region": {
+                  "startLine": 5,
+                  "startColumn": 2,
+                  "endLine": 6,
+                  "endColumn": 3
+                }
+              },
+              "logicalLocations": [
+                {
+                  "fullyQualifiedName": "example.com/pkg.Sum",
+                  "kind": "function"
+                }
+              ]
+            }
+          ]
+        },
+        {
+          "ruleId": "uncovered-statement",
+          "level": "warning",
+          "message": {
+            "text": "1 statement(s) of package level declarations not covered by any test"
+          },
+          "locations": [
+            {
+              "physicalLocation": {
+                "artifactLocation": {
+                  "uri": "pkg/a.go",
+                  "uriBaseId": "%SRCROOT%"
+                },
+                "region": {
+                  "startLine": 1,
+                  "startColumn": 10,
+                  "endLine": 1,
+                  "endColumn": 20
+                }
+              }
+            }
+          ]
+        }
+      ]
+    }
+  ]
+}
+`,
+}
+
+func TestUncoveredWriters(t *testing.T) {
+	reports := []uncoveredReport{{
+		Package: "example.com/pkg",
+		Funcs: []tester.UncoveredFunc{
+			{
+				Name: "Sum", File: "pkg/a.go", StartLine: 3, EndLine: 8, Uncovered: 2, Statements: 5,
+				Blocks: []cover.Block{{File: "pkg/a.go", StartLine: 5, StartCol: 2, EndLine: 6, EndCol: 3, NumStmt: 2}},
+			},
+			{
+				File: "pkg/a.go", StartLine: 1, EndLine: 1, Uncovered: 1, Statements: 1,
+				Blocks: []cover.Block{{File: "pkg/a.go", StartLine: 1, StartCol: 10, EndLine: 1, EndCol: 20, NumStmt: 1}},
+			},
+		},
+		sources: map[cover.BlockKey]string{
+			{File: "pkg/a.go", StartLine: 5, StartCol: 2, EndLine: 6, EndCol: 3}:   "{ return a + b }",
+			{File: "pkg/a.go", StartLine: 1, StartCol: 10, EndLine: 1, EndCol: 20}: "func() {}",
+		},
+	}}
+
+	for format, expectedOutput := range uncoveredWriterTests {
+		t.Run(format, func(t *testing.T) {
+			var b bytes.Buffer
+			if err := uncoveredWriters[format](&b, reports); err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if output := b.String(); output != expectedOutput {
+				t.Errorf("Unexpected output (expected = '%s', actual = '%s')", expectedOutput, output)
+			}
+		})
+	}
+}
+
+func TestBlockSource(t *testing.T) {
+	lines := []string{
+		"func Abs(x int) int {",
+		"\tif x < 0 {",
+		"\t\treturn -x",
+		"\t}",
+		"\treturn x",
+		"}",
+	}
+	b := cover.Block{StartLine: 2, StartCol: 11, EndLine: 4, EndCol: 3}
+	if src := blockSource(lines, b); src != "{ return -x }" {
+		t.Errorf("Unexpected source: '%s'", src)
+	}
+}
+
+func TestGitLabFingerprint(t *testing.T) {
+	fingerprints := func(startLine int, sources ...string) []string {
+		report := uncoveredReport{Funcs: []tester.UncoveredFunc{{Name: "Abs", File: "a.go"}}, sources: make(map[cover.BlockKey]string)}
+		for i, src := range sources {
+			b := cover.Block{File: "a.go", StartLine: startLine + i, EndLine: startLine + i}
+			report.Funcs[0].Blocks = append(report.Funcs[0].Blocks, b)
+			report.sources[b.Key()] = src
+		}
+		var b bytes.Buffer
+		if err := writeUncoveredGitLab(&b, []uncoveredReport{report}); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		var issues []gitlabIssue
+		if err := json.Unmarshal(b.Bytes(), &issues); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		fingerprints := []string{}
+		for _, issue := range issues {
+			fingerprints = append(fingerprints, issue.Fingerprint)
+		}
+		return fingerprints
+	}
+
+	before := fingerprints(5, "return -x", "return x")
+	// a block added before the others doesn't change their fingerprints, though they moved
+	after := fingerprints(10, "return 0", "return -x", "return x")
+	if fmt.Sprint(before) != fmt.Sprint(after[1:]) {
+		t.Errorf("Unexpected fingerprints after adding a block (before = %v, after = %v)", before, after)
+	}
+
+	// identical blocks are still distinct issues
+	if duplicates := fingerprints(5, "return x", "return x"); duplicates[0] == duplicates[1] {
+		t.Errorf("Unexpected duplicate fingerprints: %v", duplicates)
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/ShawnROGrady/go-find-tests/tester"
+)
+
+var unstableCommand = command{
+	usage:       "package",
+	description: "runs every test of the package several times and prints the blocks which a test covered in only some of its runs",
+	flags: func(fs *flag.FlagSet) func(args []string, jsonFmt bool, dst io.Writer) error {
+		var (
+			testerFlags = addTesterFlags(fs)
+			runs        = fs.Int("runs", 10, "The number of times each test is run, must be at least 2")
+		)
+		return func(args []string, jsonFmt bool, dst io.Writer) error {
+			if len(args) != 1 {
+				return &usageErr{msg: "Package argument required"}
+			}
+			if *runs < 2 {
+				return &usageErr{msg: "-runs must be at least 2"}
+			}
+
+			conf := testerFlags.config()
+			conf.Runs = *runs
+			t, err := tester.NewPackage(args[0], conf)
+			if err != nil {
+				return fmt.Errorf("Error constructing tester: %w", err)
+			}
+			unstable, err := t.UnstableBlocks()
+			if err != nil {
+				return fmt.Errorf("Error running tests: %w", err)
+			}
+			if err := printUnstableBlocks(dst, unstable, jsonFmt); err != nil {
+				return fmt.Errorf("Error writing output: %w", err)
+			}
+			return nil
+		}
+	},
+}
+
+// printUnstableBlocks prints each unstable block as '<file>:<start line>.<start col>,<end line>.<end col> <test> <covered>/<runs> runs'
+func printUnstableBlocks(dst io.Writer, unstable []tester.UnstableBlock, jsonFmt bool) error {
+	if jsonFmt {
+		b, err := json.Marshal(unstable)
+		if err != nil {
+			return err
+		}
+		_, err = dst.Write(b)
+		return err
+	}
+
+	w := tabwriter.NewWriter(dst, 0, 0, 2, ' ', 0)
+	for _, u := range unstable {
+		b := u.Block
+		if _, err := fmt.Fprintf(w, "%s:%d.%d,%d.%d\t%s\t%d/%d runs\n", b.File, b.StartLine, b.StartCol, b.EndLine, b.EndCol, u.Test, u.CoveredRuns, u.Runs); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ShawnROGrady/go-find-tests/cover"
+	"github.com/ShawnROGrady/go-find-tests/tester"
+)
+
+func TestPrintUnstableBlocks(t *testing.T) {
+	unstable := []tester.UnstableBlock{
+		{Block: cover.Block{File: "parity.go", StartLine: 5, StartCol: 15, EndLine: 7, EndCol: 3, NumStmt: 1, Count: 2}, Test: "TestAlternating", Runs: 4, CoveredRuns: 2},
+		{Block: cover.Block{File: "parity.go", StartLine: 8, StartCol: 2, EndLine: 8, EndCol: 14, NumStmt: 1, Count: 1}, Test: "TestA", Runs: 4, CoveredRuns: 1},
+	}
+	expected := map[bool]string{
+		false: "parity.go:5.15,7.3  TestAlternating  2/4 runs\n" +
+			"parity.go:8.2,8.14  TestA            1/4 runs\n",
+		true: `[{"block":{"file":"parity.go","start_line":5,"start_col":15,"end_line":7,"end_col":3,"num_stmt":1,"count":2},"test":"TestAlternating","runs":4,"covered_runs":2},` +
+			`{"block":{"file":"parity.go","start_line":8,"start_col":2,"end_line":8,"end_col":14,"num_stmt":1,"count":1},"test":"TestA","runs":4,"covered_runs":1}]`,
+	}
+	for jsonFmt, expectedOutput := range expected {
+		var b bytes.Buffer
+		if err := printUnstableBlocks(&b, unstable, jsonFmt); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if output := b.String(); output != expectedOutput {
+			t.Errorf("Unexpected output with json = %t (expected = '%s', actual = '%s')", jsonFmt, expectedOutput, output)
+		}
+	}
+}
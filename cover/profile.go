@@ -60,17 +60,142 @@ func New(r io.Reader) (*Profile, error) {
 }
 
 // Covers returns whether or not the statement at the given position is covered by the profile
+// a col of 0 means no column was specified, in which case any covered block on the line counts
 func (p *Profile) Covers(file string, line, col int) bool {
+	_, ok := p.CoveringBlock(file, line, col)
+	return ok
+}
+
+// CoveringBlock returns the covered block containing the statement at the given position
+// the returned bool is false if the position is not covered by the profile
+func (p *Profile) CoveringBlock(file string, line, col int) (Block, bool) {
 	if prof, ok := (*p)[file]; ok {
+		if col == 0 {
+			if i := prof.coveringLine(line); i != -1 {
+				return prof[i].block(file), true
+			}
+			return Block{}, false
+		}
 		for i := range prof {
 			if prof[i].inBlock(line, col) {
-				return prof[i].count != 0
+				return prof[i].block(file), prof[i].count != 0
 			}
 		}
 	}
+	return Block{}, false
+}
+
+// CoveredStatements returns the total number of statements covered by the profile
+func (p *Profile) CoveredStatements() int {
+	var stmts int
+	for _, blocks := range *p {
+		for i := range blocks {
+			if blocks[i].count != 0 {
+				stmts += blocks[i].numStmt
+			}
+		}
+	}
+	return stmts
+}
+
+// Subtract returns a copy of the profile with the count of each block reduced by its count in baseline, to at least zero
+// e.g. to exclude the coverage of package initialization from the profile of a test
+func (p *Profile) Subtract(baseline *Profile) *Profile {
+	diff := make(Profile, len(*p))
+	for file, blocks := range *p {
+		baseCounts := make(map[coverBlock]int)
+		for _, b := range (*baseline)[file] {
+			count := b.count
+			b.count = 0
+			baseCounts[b] = count
+		}
+
+		fileDiff := make(coverBlocks, len(blocks))
+		for i, b := range blocks {
+			key := b
+			key.count = 0
+			b.count -= baseCounts[key]
+			if b.count < 0 {
+				b.count = 0
+			}
+			fileDiff[i] = b
+		}
+		diff[file] = fileDiff
+	}
+	return &diff
+}
+
+// Blocks returns every block of the profile, ordered by file and then position
+func (p *Profile) Blocks() []Block {
+	blocks := []Block{}
+	for file, fileBlocks := range *p {
+		for i := range fileBlocks {
+			blocks = append(blocks, fileBlocks[i].block(file))
+		}
+	}
+	SortBlocks(blocks)
+	return blocks
+}
+
+// SortBlocks orders blocks by file and then position
+func SortBlocks(blocks []Block) {
+	sort.Slice(blocks, func(i, j int) bool { return blocks[i].Before(blocks[j]) })
+}
+
+// Block represents a single block of a cover profile
+type Block struct {
+	File      string `json:"file"`
+	StartLine int    `json:"start_line"`
+	StartCol  int    `json:"start_col"`
+	EndLine   int    `json:"end_line"`
+	EndCol    int    `json:"end_col"`
+	NumStmt   int    `json:"num_stmt"`
+	Count     int    `json:"count"`
+}
+
+// Key returns the key identifying the block independent of its count
+func (b Block) Key() BlockKey {
+	return BlockKey{
+		File:      b.File,
+		StartLine: b.StartLine,
+		StartCol:  b.StartCol,
+		EndLine:   b.EndLine,
+		EndCol:    b.EndCol,
+	}
+}
+
+// Contains returns whether the position is within the block
+func (b Block) Contains(line, col int) bool {
+	if b.StartLine <= line && b.EndLine >= line {
+		if b.StartLine == line && b.EndLine == line {
+			return b.StartCol <= col && b.EndCol >= col
+		}
+		if b.StartLine == line {
+			return b.StartCol <= col
+		}
+		if b.EndLine == line {
+			return b.EndCol >= col
+		}
+		return true
+	}
 	return false
 }
 
+// Before returns whether the block is ordered before other by file and then position
+func (b Block) Before(other Block) bool {
+	switch {
+	case b.File != other.File:
+		return b.File < other.File
+	case b.StartLine != other.StartLine:
+		return b.StartLine < other.StartLine
+	case b.StartCol != other.StartCol:
+		return b.StartCol < other.StartCol
+	case b.EndLine != other.EndLine:
+		return b.EndLine < other.EndLine
+	}
+	return b.EndCol < other.EndCol
+}
+
 // alias to implement sort.Interface
 type coverBlocks []coverBlock
 
@@ -89,6 +214,16 @@ func (c coverBlocks) Swap(i, j int) {
 	c[i], c[j] = c[j], c[i]
 }
 
+// coveringLine returns the index of the first covered block spanning the line, or -1 if there is none
+func (c coverBlocks) coveringLine(line int) int {
+	for i := range c {
+		if c[i].startLine <= line && c[i].endLine >= line && c[i].count != 0 {
+			return i
+		}
+	}
+	return -1
+}
+
 type coverBlock struct {
 	startLine int
 	startCol  int
@@ -98,20 +233,20 @@ type coverBlock struct {
 	count     int
 }
 
-func (c coverBlock) inBlock(line, col int) bool {
-	if c.startLine <= line && c.endLine >= line {
-		if c.startLine == line && c.endLine == line {
-			return c.startCol <= col && c.endCol >= col
-		}
-		if c.startLine == line {
-			return c.startCol <= col
-		}
-		if c.endLine == line {
-			return c.endCol >= col
-		}
-		return true
+func (c coverBlock) block(file string) Block {
+	return Block{
+		File:      file,
+		StartLine: c.startLine,
+		StartCol:  c.startCol,
+		EndLine:   c.endLine,
+		EndCol:    c.endCol,
+		NumStmt:   c.numStmt,
+		Count:     c.count,
 	}
-	return false
+}
+
+func (c coverBlock) inBlock(line, col int) bool {
+	return c.block("").Contains(line, col)
 }
 
 type coverLine struct {
@@ -2,6 +2,7 @@ package cover
 
 import (
 	"bytes"
+	"fmt"
 	"testing"
 )
 
@@ -54,6 +55,18 @@ var coverTests = map[string]struct {
 		line:  86, col: 4,
 		expectCovered: true,
 	},
+	"no_column_covered_line": {
+		cover: coverOut,
+		file:  "format.go",
+		line:  72, col: 0,
+		expectCovered: true,
+	},
+	"no_column_uncovered_line": {
+		cover: coverOut,
+		file:  "format.go",
+		line:  74, col: 0,
+		expectCovered: false,
+	},
 	"uncovered_file": {
 		cover: coverOut,
 		file:  "fake_file.go",
@@ -81,6 +94,57 @@ func TestCovers(t *testing.T) {
 	}
 }
 
+var coveringBlockTests = map[string]struct {
+	cover         string
+	file          string
+	line, col     int
+	expectCovered bool
+	expectedBlock Block
+}{
+	"covered_position": {
+		cover: coverOut,
+		file:  "format.go",
+		line:  86, col: 4,
+		expectCovered: true,
+		expectedBlock: Block{File: "format.go", StartLine: 86, StartCol: 2, EndLine: 86, EndCol: 23, NumStmt: 1, Count: 1},
+	},
+	"covered_line": {
+		cover: coverOut,
+		file:  "format.go",
+		line:  79, col: 0,
+		expectCovered: true,
+		expectedBlock: Block{File: "format.go", StartLine: 78, StartCol: 12, EndLine: 80, EndCol: 3, NumStmt: 1, Count: 1},
+	},
+	"uncovered_position": {
+		cover: coverOut,
+		file:  "format.go",
+		line:  73, col: 0,
+		expectCovered: false,
+	},
+}
+
+func TestCoveringBlock(t *testing.T) {
+	for testName, test := range coveringBlockTests {
+		t.Run(testName, func(t *testing.T) {
+			var b bytes.Buffer
+			b.WriteString(test.cover)
+
+			profile, err := New(&b)
+			if err != nil {
+				t.Fatalf("Error creating profile: %s", err)
+			}
+
+			block, covered := profile.CoveringBlock(test.file, test.line, test.col)
+			if covered != test.expectCovered {
+				t.Errorf("Unexpected coverage result (expected = %v, actual = %v)", test.expectCovered, covered)
+			}
+			if covered && block != test.expectedBlock {
+				t.Errorf("Unexpected block (expected = %#v, actual = %#v)", test.expectedBlock, block)
+			}
+		})
+	}
+}
+
 var parseLineTests = map[string]struct {
 	line           string
 	expectErr      bool
@@ -157,3 +221,59 @@ func TestParseLine(t *testing.T) {
 		})
 	}
 }
+
+func TestBlocks(t *testing.T) {
+	prof, err := New(bytes.NewBufferString(coverOut))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	blocks := prof.Blocks()
+	if len(blocks) != 17 {
+		t.Fatalf("Unexpected number of blocks (expected = 17, actual = %d)", len(blocks))
+	}
+	for i := 1; i < len(blocks); i++ {
+		if !blocks[i-1].Before(blocks[i]) {
+			t.Errorf("Blocks out of order: %v before %v", blocks[i-1], blocks[i])
+		}
+	}
+	if first := blocks[0]; first.File != "errors.go" || first.StartLine != 17 || first.NumStmt != 6 {
+		t.Errorf("Unexpected first block: %v", first)
+	}
+}
+
+func TestSubtract(t *testing.T) {
+	prof, err := New(bytes.NewBufferString(`mode: count
+fmt/errors.go:17.52,23.25 6 3
+fmt/errors.go:28.2,29.12 2 1
+fmt/format.go:54.28,56.2 1 2`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	baseline, err := New(bytes.NewBufferString(`mode: count
+fmt/errors.go:17.52,23.25 6 1
+fmt/errors.go:28.2,29.12 2 2
+fmt/print.go:10.1,11.2 1 1`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	expected := map[string]int{
+		"errors.go:17": 2, // covered by the test beyond the baseline
+		"errors.go:28": 0, // covered by the test no more than the baseline
+		"format.go:54": 2, // not covered by the baseline
+	}
+	blocks := prof.Subtract(baseline).Blocks()
+	if len(blocks) != len(expected) {
+		t.Fatalf("Unexpected number of blocks (expected = %d, actual = %d)", len(expected), len(blocks))
+	}
+	for _, b := range blocks {
+		key := fmt.Sprintf("%s:%d", b.File, b.StartLine)
+		if b.Count != expected[key] {
+			t.Errorf("Unexpected count of %s (expected = %d, actual = %d)", key, expected[key], b.Count)
+		}
+	}
+	if covered, _ := prof.CoveringBlock("errors.go", 28, 0); covered.Count != 1 {
+		t.Errorf("Subtract modified the original profile (count = %d)", covered.Count)
+	}
+}
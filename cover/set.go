@@ -0,0 +1,91 @@
+package cover
+
+// BlockKey identifies a block of a cover profile independent of its count
+type BlockKey struct {
+	File      string
+	StartLine int
+	StartCol  int
+	EndLine   int
+	EndCol    int
+}
+
+// Set is a set of covered blocks, each mapped to the number of statements it contains
+type Set map[BlockKey]int
+
+// Covered returns the set of blocks covered by the profile
+func (p *Profile) Covered() Set {
+	set := make(Set)
+	for file, blocks := range *p {
+		for i := range blocks {
+			if blocks[i].count == 0 {
+				continue
+			}
+			set[blocks[i].key(file)] = blocks[i].numStmt
+		}
+	}
+	return set
+}
+
+func (c coverBlock) key(file string) BlockKey {
+	return BlockKey{
+		File:      file,
+		StartLine: c.startLine,
+		StartCol:  c.startCol,
+		EndLine:   c.endLine,
+		EndCol:    c.endCol,
+	}
+}
+
+// Statements returns the total number of statements in the set
+func (s Set) Statements() int {
+	var stmts int
+	for _, n := range s {
+		stmts += n
+	}
+	return stmts
+}
+
+// Union returns the blocks which are in either set
+func (s Set) Union(other Set) Set {
+	union := make(Set, len(s))
+	for k, n := range s {
+		union[k] = n
+	}
+	for k, n := range other {
+		union[k] = n
+	}
+	return union
+}
+
+// Intersect returns the blocks which are in both sets
+func (s Set) Intersect(other Set) Set {
+	intersection := make(Set)
+	for k, n := range s {
+		if _, ok := other[k]; ok {
+			intersection[k] = n
+		}
+	}
+	return intersection
+}
+
+// Difference returns the blocks of s which are not in other
+func (s Set) Difference(other Set) Set {
+	diff := make(Set)
+	for k, n := range s {
+		if _, ok := other[k]; !ok {
+			diff[k] = n
+		}
+	}
+	return diff
+}
+
+// Lines returns the blocks of the file which overlap the inclusive range of lines
+func (s Set) Lines(file string, start, end int) Set {
+	lines := make(Set)
+	for k, n := range s {
+		if k.File == file && k.StartLine <= end && k.EndLine >= start {
+			lines[k] = n
+		}
+	}
+	return lines
+}
@@ -0,0 +1,76 @@
+package cover
+
+import (
+	"strings"
+	"testing"
+)
+
+const (
+	setCoverA = `mode: count
+pkg/a.go:3.10,5.2 2 1
+pkg/a.go:7.10,9.2 1 3
+pkg/a.go:11.10,15.2 4 0`
+	setCoverB = `mode: count
+pkg/a.go:3.10,5.2 2 0
+pkg/a.go:7.10,9.2 1 1
+pkg/a.go:11.10,15.2 4 2`
+)
+
+func mustSet(t *testing.T, cover string) Set {
+	t.Helper()
+	prof, err := New(strings.NewReader(cover))
+	if err != nil {
+		t.Fatalf("Unexpected error parsing profile: %s", err)
+	}
+	return prof.Covered()
+}
+
+var setTests = map[string]struct {
+	op            func(a, b Set) Set
+	expectedStmts int
+	expectedLen   int
+}{
+	"union": {
+		op:            Set.Union,
+		expectedStmts: 7,
+		expectedLen:   3,
+	},
+	"intersect": {
+		op:            Set.Intersect,
+		expectedStmts: 1,
+		expectedLen:   1,
+	},
+	"difference": {
+		op:            Set.Difference,
+		expectedStmts: 2,
+		expectedLen:   1,
+	},
+	"lines": {
+		op:            func(a, b Set) Set { return a.Union(b).Lines("a.go", 8, 12) },
+		expectedStmts: 5,
+		expectedLen:   2,
+	},
+	"lines_other_file": {
+		op:            func(a, b Set) Set { return a.Union(b).Lines("b.go", 1, 20) },
+		expectedStmts: 0,
+		expectedLen:   0,
+	},
+}
+
+func TestSetOperations(t *testing.T) {
+	a, b := mustSet(t, setCoverA), mustSet(t, setCoverB)
+	if stmts := a.Statements(); stmts != 3 {
+		t.Fatalf("Unexpected covered statements (expected = 3, actual = %d)", stmts)
+	}
+	for testName, test := range setTests {
+		t.Run(testName, func(t *testing.T) {
+			set := test.op(a, b)
+			if len(set) != test.expectedLen {
+				t.Errorf("Unexpected number of blocks (expected = %d, actual = %d)", test.expectedLen, len(set))
+			}
+			if stmts := set.Statements(); stmts != test.expectedStmts {
+				t.Errorf("Unexpected number of statements (expected = %d, actual = %d)", test.expectedStmts, stmts)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package finder
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"strings"
+	"time"
+)
+
+// directivePrefix begins each comment which configures how a test is run
+const directivePrefix = "//gofindtests:"
+
+// Directives configure how a test is run, from '//gofindtests:' comments in the doc comment of its declaration
+type Directives struct {
+	Skip    bool          `json:"skip,omitempty"`    // '//gofindtests:skip', the test is never run, e.g. a slow end-to-end test
+	Serial  bool          `json:"serial,omitempty"`  // '//gofindtests:serial', the test never runs concurrently with any other test
+	Groups  []string      `json:"groups,omitempty"`  // '//gofindtests:group <name>', the test never runs concurrently with other tests of the group
+	Timeout time.Duration `json:"timeout,omitempty"` // '//gofindtests:timeout <duration>', e.g. '2m', overrides the configured timeout of the test
+}
+
+// DirectiveError is returned for a malformed or unknown directive
+type DirectiveError struct {
+	Pos       token.Position
+	Directive string
+	Err       error
+}
+
+func (e *DirectiveError) Error() string {
+	return fmt.Sprintf("%s: %s: %s", e.Pos, e.Directive, e.Err)
+}
+
+func (e *DirectiveError) Unwrap() error {
+	return e.Err
+}
+
+// PackageDirectives returns the directives of each test within a package which has at least one
+func PackageDirectives(dir string) (map[string]Directives, error) {
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, dir, func(f os.FileInfo) bool {
+		return strings.HasSuffix(f.Name(), "_test.go")
+	}, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	directives := make(map[string]Directives)
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Files {
+			for _, decl := range file.Decls {
+				fn, ok := decl.(*ast.FuncDecl)
+				if !ok || fn.Recv != nil || fn.Doc == nil || !isTestFunc(fn.Name.Name) {
+					continue
+				}
+				d, found, err := parseDirectives(fset, fn.Doc)
+				if err != nil {
+					return nil, err
+				}
+				if found {
+					directives[fn.Name.Name] = d
+				}
+			}
+		}
+	}
+	return directives, nil
+}
+
+// parseDirectives parses the directives within a doc comment, returning whether there were any
+func parseDirectives(fset *token.FileSet, doc *ast.CommentGroup) (Directives, bool, error) {
+	var (
+		d     Directives
+		found bool
+	)
+	for _, c := range doc.List {
+		if !strings.HasPrefix(c.Text, directivePrefix) {
+			continue
+		}
+		found = true
+		fields := strings.Fields(strings.TrimPrefix(c.Text, directivePrefix))
+		if len(fields) == 0 {
+			return Directives{}, false, &DirectiveError{Pos: fset.Position(c.Pos()), Directive: c.Text, Err: fmt.Errorf("missing directive name")}
+		}
+		derr := func(format string, args ...interface{}) error {
+			return &DirectiveError{Pos: fset.Position(c.Pos()), Directive: fields[0], Err: fmt.Errorf(format, args...)}
+		}
+
+		switch fields[0] {
+		case "skip", "serial":
+			if len(fields) != 1 {
+				return Directives{}, false, derr("expected no arguments")
+			}
+			d.Skip = d.Skip || fields[0] == "skip"
+			d.Serial = d.Serial || fields[0] == "serial"
+		case "group":
+			if len(fields) != 2 {
+				return Directives{}, false, derr("expected a single group name")
+			}
+			d.Groups = append(d.Groups, fields[1])
+		case "timeout":
+			if len(fields) != 2 {
+				return Directives{}, false, derr("expected a single duration")
+			}
+			timeout, err := time.ParseDuration(fields[1])
+			if err != nil || timeout <= 0 {
+				return Directives{}, false, derr("invalid duration '%s'", fields[1])
+			}
+			d.Timeout = timeout
+		default:
+			return Directives{}, false, derr("unknown directive")
+		}
+	}
+	return d, found, nil
+}
@@ -0,0 +1,110 @@
+package finder
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var packageDirectivesTests = map[string]struct {
+	src        string
+	expected   map[string]Directives
+	expectErr  bool
+	errMessage string
+}{
+	"serial_and_groups": {
+		src: `package p
+
+import "testing"
+
+// TestA is serial
+//gofindtests:serial
+func TestA(t *testing.T) {}
+
+//gofindtests:group db
+//gofindtests:group cache
+func TestB(t *testing.T) {}
+
+// TestC has no directives
+func TestC(t *testing.T) {}
+
+//gofindtests:serial
+func helper() {}
+
+// TestD is slow
+//gofindtests:skip
+//gofindtests:timeout 2m
+func TestD(t *testing.T) {}
+`,
+		expected: map[string]Directives{
+			"TestA": {Serial: true},
+			"TestB": {Groups: []string{"db", "cache"}},
+			"TestD": {Skip: true, Timeout: 2 * time.Minute},
+		},
+	},
+	"unknown_directive": {
+		src: `package p
+
+//gofindtests:sequential
+func TestA(t *testing.T) {}
+`,
+		expectErr:  true,
+		errMessage: "p_test.go:3:1: sequential: unknown directive",
+	},
+	"invalid_timeout": {
+		src: `package p
+
+//gofindtests:timeout soon
+func TestA(t *testing.T) {}
+`,
+		expectErr:  true,
+		errMessage: "p_test.go:3:1: timeout: invalid duration 'soon'",
+	},
+	"group_without_name": {
+		src: `package p
+
+//gofindtests:group
+func TestA(t *testing.T) {}
+`,
+		expectErr:  true,
+		errMessage: "p_test.go:3:1: group: expected a single group name",
+	},
+}
+
+func TestPackageDirectives(t *testing.T) {
+	for testName, test := range packageDirectivesTests {
+		test := test
+		t.Run(testName, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "directives")
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			defer os.RemoveAll(dir)
+			if err := ioutil.WriteFile(filepath.Join(dir, "p_test.go"), []byte(test.src), 0644); err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+
+			directives, err := PackageDirectives(dir)
+			if test.expectErr {
+				var directiveErr *DirectiveError
+				if !errors.As(err, &directiveErr) {
+					t.Fatalf("Unexpected error (expected = DirectiveError, actual = %v)", err)
+				}
+				if msg := filepath.Base(err.Error()); msg != test.errMessage {
+					t.Errorf("Unexpected error message (expected = %s, actual = %s)", test.errMessage, msg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if fmt.Sprint(directives) != fmt.Sprint(test.expected) {
+				t.Errorf("Unexpected directives (expected = %v, actual = %v)", test.expected, directives)
+			}
+		})
+	}
+}
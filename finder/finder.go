@@ -16,7 +16,7 @@ type TestPosition struct {
 	Offset int    `json:"offset"`
 }
 
-// PackageTests returns the positions of all tests within a package
+// PackageTests returns the positions of all tests, examples, benchmarks, and fuzz targets within a package
 func PackageTests(dir string) (map[string]TestPosition, error) {
 	fset := token.NewFileSet()
 	pkgs, err := parser.ParseDir(fset, dir, func(f os.FileInfo) bool {
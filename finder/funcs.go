@@ -0,0 +1,70 @@
+package finder
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+)
+
+// Func is a function or method declared in a file
+type Func struct {
+	Name      string `json:"name"` // e.g. 'New', 'Profile.Covers', or '(*Profile).Covers'
+	File      string `json:"file"`
+	StartLine int    `json:"start_line"`
+	EndLine   int    `json:"end_line"`
+}
+
+// Funcs returns the functions and methods declared in the files of dir, File is the name of the file within dir
+func Funcs(dir string, files []string) ([]Func, error) {
+	var (
+		funcs = []Func{}
+		fset  = token.NewFileSet()
+	)
+	for _, name := range files {
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok {
+				continue
+			}
+			funcs = append(funcs, Func{
+				Name:      funcName(fn),
+				File:      name,
+				StartLine: fset.Position(fn.Pos()).Line,
+				EndLine:   fset.Position(fn.End()).Line,
+			})
+		}
+	}
+	return funcs, nil
+}
+
+// funcName returns the name of the function, qualified by its receiver type if it is a method
+func funcName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return fn.Name.Name
+	}
+	var (
+		recv    = fn.Recv.List[0].Type
+		pointer bool
+	)
+	if star, ok := recv.(*ast.StarExpr); ok {
+		pointer = true
+		recv = star.X
+	}
+	if index, ok := recv.(*ast.IndexExpr); ok {
+		// generic receiver
+		recv = index.X
+	}
+	typeName := "?"
+	if ident, ok := recv.(*ast.Ident); ok {
+		typeName = ident.Name
+	}
+	if pointer {
+		return "(*" + typeName + ")." + fn.Name.Name
+	}
+	return typeName + "." + fn.Name.Name
+}
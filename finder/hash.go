@@ -0,0 +1,95 @@
+package finder
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// SourceHashes are hashes of the source of a package, used to determine which tests are affected by a change
+type SourceHashes struct {
+	Tests  map[string]string // hash of the declaration of each test function, including its doc comment
+	Shared string            // hash of all other source in the package directory
+}
+
+// Equal returns whether the hashes are identical
+func (s SourceHashes) Equal(other SourceHashes) bool {
+	if s.Shared != other.Shared || len(s.Tests) != len(other.Tests) {
+		return false
+	}
+	for test, hash := range s.Tests {
+		if other.Tests[test] != hash {
+			return false
+		}
+	}
+	return true
+}
+
+// PackageHashes hashes the go files of the package in dir
+//
+// a change to a test function only changes its own hash,
+// any other change (e.g. to a helper function or non-test file) changes the shared hash
+func PackageHashes(dir string) (SourceHashes, error) {
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return SourceHashes{}, err
+	}
+	names := []string{}
+	for _, info := range infos {
+		if !info.IsDir() && strings.HasSuffix(info.Name(), ".go") {
+			names = append(names, info.Name())
+		}
+	}
+	sort.Strings(names)
+
+	var (
+		hashes = SourceHashes{Tests: make(map[string]string)}
+		shared = sha256.New()
+		fset   = token.NewFileSet()
+	)
+	for _, name := range names {
+		src, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return SourceHashes{}, err
+		}
+		shared.Write([]byte(name))
+		if !strings.HasSuffix(name, "_test.go") {
+			shared.Write(src)
+			continue
+		}
+
+		file, err := parser.ParseFile(fset, name, src, parser.ParseComments)
+		if err != nil {
+			return SourceHashes{}, err
+		}
+		tokFile := fset.File(file.Pos())
+		start := 0
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Recv != nil || !isTestFunc(fn.Name.Name) {
+				continue
+			}
+			declStart := fn.Pos()
+			if fn.Doc != nil {
+				declStart = fn.Doc.Pos()
+			}
+			from, to := tokFile.Offset(declStart), tokFile.Offset(fn.End())
+
+			sum := sha256.Sum256(src[from:to])
+			hashes.Tests[fn.Name.Name] = hex.EncodeToString(sum[:])
+			// whitespace between declarations is ignored so that adding a test doesn't affect the others
+			shared.Write(bytes.TrimSpace(src[start:from]))
+			start = to
+		}
+		shared.Write(bytes.TrimSpace(src[start:]))
+	}
+	hashes.Shared = hex.EncodeToString(shared.Sum(nil))
+	return hashes, nil
+}
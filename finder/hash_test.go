@@ -0,0 +1,145 @@
+package finder
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+const (
+	hashSource = `package pkg
+
+func double(n int) int { return 2 * n }
+`
+	hashTestSource = `package pkg
+
+import "testing"
+
+// TestDouble checks double
+func TestDouble(t *testing.T) {
+	check(t, double(2), 4)
+}
+
+func TestDoubleZero(t *testing.T) {
+	check(t, double(0), 0)
+}
+
+func check(t *testing.T, actual, expected int) {
+	if actual != expected {
+		t.Errorf("Unexpected result (expected = %d, actual = %d)", expected, actual)
+	}
+}
+`
+)
+
+var packageHashesTests = map[string]struct {
+	files                map[string]string // replaced or added files
+	expectedChangedTests []string
+	expectSharedChanged  bool
+}{
+	"unchanged": {
+		files: map[string]string{},
+	},
+	"test_body_changed": {
+		files: map[string]string{
+			"pkg_test.go": replace(hashTestSource, "double(2), 4", "double(3), 6"),
+		},
+		expectedChangedTests: []string{"TestDouble"},
+	},
+	"test_doc_changed": {
+		files: map[string]string{
+			"pkg_test.go": replace(hashTestSource, "checks double", "checks double of 2"),
+		},
+		expectedChangedTests: []string{"TestDouble"},
+	},
+	"test_added": {
+		files: map[string]string{
+			"pkg_test.go": hashTestSource + "\nfunc TestDoubleNegative(t *testing.T) {\n\tcheck(t, double(-1), -2)\n}\n",
+		},
+		expectedChangedTests: []string{"TestDoubleNegative"},
+	},
+	"helper_changed": {
+		files: map[string]string{
+			"pkg_test.go": replace(hashTestSource, "actual != expected", "expected != actual"),
+		},
+		expectSharedChanged: true,
+	},
+	"source_changed": {
+		files: map[string]string{
+			"pkg.go": replace(hashSource, "2 * n", "n + n"),
+		},
+		expectSharedChanged: true,
+	},
+	"file_added": {
+		files: map[string]string{
+			"other.go": "package pkg\n",
+		},
+		expectSharedChanged: true,
+	},
+}
+
+func replace(s, old, new string) string {
+	return strings.Replace(s, old, new, 1)
+}
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, src := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(src), 0644); err != nil {
+			t.Fatalf("Unexpected error writing %s: %s", name, err)
+		}
+	}
+}
+
+func TestPackageHashes(t *testing.T) {
+	for testName, test := range packageHashesTests {
+		t.Run(testName, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "package_hashes")
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			defer os.RemoveAll(dir)
+
+			writeFiles(t, dir, map[string]string{"pkg.go": hashSource, "pkg_test.go": hashTestSource})
+			before, err := PackageHashes(dir)
+			if err != nil {
+				t.Fatalf("Unexpected error hashing package: %s", err)
+			}
+			if len(before.Tests) != 2 {
+				t.Fatalf("Unexpected number of tests hashed (expected = 2, actual = %d)", len(before.Tests))
+			}
+
+			writeFiles(t, dir, test.files)
+			after, err := PackageHashes(dir)
+			if err != nil {
+				t.Fatalf("Unexpected error hashing changed package: %s", err)
+			}
+
+			changed := []string{}
+			for name, hash := range after.Tests {
+				if before.Tests[name] != hash {
+					changed = append(changed, name)
+				}
+			}
+			sort.Strings(changed)
+			if len(changed) != len(test.expectedChangedTests) {
+				t.Errorf("Unexpected changed tests (expected = %v, actual = %v)", test.expectedChangedTests, changed)
+			} else {
+				for i := range changed {
+					if changed[i] != test.expectedChangedTests[i] {
+						t.Errorf("Unexpected changed tests (expected = %v, actual = %v)", test.expectedChangedTests, changed)
+					}
+				}
+			}
+			if sharedChanged := before.Shared != after.Shared; sharedChanged != test.expectSharedChanged {
+				t.Errorf("Unexpected change to shared hash (expected = %t, actual = %t)", test.expectSharedChanged, sharedChanged)
+			}
+			if unchanged := len(test.files) == 0; before.Equal(after) != unchanged {
+				t.Errorf("Unexpected equality of hashes (expected = %t)", unchanged)
+			}
+		})
+	}
+}
@@ -6,6 +6,9 @@ import (
 	"strings"
 )
 
+// testFuncPrefixes are the prefixes of the functions run by 'go test'
+var testFuncPrefixes = []string{"Test", "Example", "Benchmark", "Fuzz"}
+
 type testFuncFinder struct {
 	fset      *token.FileSet
 	testFuncs map[string]TestPosition
@@ -15,7 +18,7 @@ func (f *testFuncFinder) Visit(node ast.Node) ast.Visitor {
 	switch n := node.(type) {
 	case *ast.FuncDecl:
 		fun := n.Type
-		if !strings.HasPrefix(n.Name.Name, "Test") {
+		if !isTestFunc(n.Name.Name) {
 			return nil
 		}
 		currentFile := f.fset.File(fun.Func)
@@ -31,3 +34,12 @@ func (f *testFuncFinder) Visit(node ast.Node) ast.Visitor {
 	}
 	return f
 }
+
+func isTestFunc(name string) bool {
+	for _, prefix := range testFuncPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
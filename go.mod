@@ -1,5 +1,5 @@
 module github.com/ShawnROGrady/go-find-tests
 
-go 1.13
+go 1.20
 
 require golang.org/x/sync v0.0.0-20190911185100-cd5d95a43a6e
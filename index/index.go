@@ -0,0 +1,206 @@
+// Package index maintains a coverage index of every test of a module,
+// which can answer which tests cover a position without running any of them
+package index
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/ShawnROGrady/go-find-tests/cover"
+	"github.com/ShawnROGrady/go-find-tests/finder"
+	"github.com/ShawnROGrady/go-find-tests/tester"
+)
+
+// Version is the version of the index format, indexes of any other version are rebuilt from scratch
+const Version = 1
+
+// Index is the coverage of every test of a module
+// all file paths are slash separated and relative to the root of the module
+type Index struct {
+	Version  int        `json:"version"`
+	Module   string     `json:"module"`
+	Options  Options    `json:"options"`
+	Packages []*Package `json:"packages"` // sorted by import path
+}
+
+// Options are the options of the tester which affect the contents of the index
+type Options struct {
+	Short      bool   `json:"short,omitempty"`
+	Run        string `json:"run,omitempty"`
+	Skip       string `json:"skip,omitempty"`
+	Benchmarks bool   `json:"benchmarks,omitempty"`
+	Tags       string `json:"tags,omitempty"`
+}
+
+func optionsOf(conf tester.Config) Options {
+	return Options{Short: conf.Short, Run: conf.Run, Skip: conf.Skip, Benchmarks: conf.Benchmarks, Tags: conf.Tags}
+}
+
+// Package is the coverage of the tests of a single package
+type Package struct {
+	ImportPath string `json:"import_path"`
+	Dir        string `json:"dir"`
+	Hash       string `json:"hash"` // hash of every input of the test binary, see tester.ModulePackage
+	// Error is set if the tests of the package could not be run, such packages are always re-indexed
+	Error  string        `json:"error,omitempty"`
+	Blocks []cover.Block `json:"blocks"` // ordered by file and position, Count is the total over all tests
+	Funcs  []Func        `json:"funcs"`
+	Tests  []Test        `json:"tests"` // sorted by name
+}
+
+// Func is a function or method declared in a non-test file of a package
+type Func = finder.Func
+
+// Test is the coverage of a single top-level test
+type Test struct {
+	Name    string        `json:"name"`
+	Kind    tester.Kind   `json:"kind"`
+	File    string        `json:"file,omitempty"`
+	Line    int           `json:"line,omitempty"`
+	Elapsed time.Duration `json:"elapsed"`
+	Status  tester.Status `json:"status"`
+	Blocks  []int         `json:"blocks"` // indexes of the blocks of the package executed by the test
+	Counts  []int         `json:"counts"` // number of times each of Blocks was executed
+}
+
+// Read decodes an index written by Write
+func Read(r io.Reader) (*Index, error) {
+	idx := &Index{}
+	if err := json.NewDecoder(r).Decode(idx); err != nil {
+		return nil, fmt.Errorf("error decoding index: %w", err)
+	}
+	if idx.Version != Version {
+		return nil, fmt.Errorf("unsupported index version %d (expected %d)", idx.Version, Version)
+	}
+	return idx, nil
+}
+
+// Write encodes the index
+func (idx *Index) Write(w io.Writer) error {
+	return json.NewEncoder(w).Encode(idx)
+}
+
+// Build indexes every package of the module which owns dir
+//
+// packages whose inputs are unchanged since prev (which may be nil) was built are copied from it rather than re-run,
+// progress (which may be nil) is called after each package with whether it was copied and the error indexing it, if any.
+// a package which could not be indexed is still included, with its Error set
+func Build(dir string, conf tester.Config, prev *Index, progress func(pkg string, reused bool, err error)) (*Index, error) {
+	mod, err := tester.ListModule(dir, conf.Tags)
+	if err != nil {
+		return nil, fmt.Errorf("error listing module: %w", err)
+	}
+	if progress == nil {
+		progress = func(string, bool, error) {}
+	}
+
+	idx := &Index{
+		Version:  Version,
+		Module:   mod.Path,
+		Options:  optionsOf(conf),
+		Packages: []*Package{},
+	}
+	reusable := make(map[string]*Package)
+	if prev != nil && prev.Version == idx.Version && prev.Module == idx.Module && prev.Options == idx.Options {
+		for _, pkg := range prev.Packages {
+			if pkg.Error == "" {
+				reusable[pkg.ImportPath] = pkg
+			}
+		}
+	}
+
+	for _, listed := range mod.Packages {
+		if pkg, ok := reusable[listed.ImportPath]; ok && pkg.Hash == listed.Hash {
+			idx.Packages = append(idx.Packages, pkg)
+			progress(listed.ImportPath, true, nil)
+			continue
+		}
+		pkg, err := indexPackage(mod.Dir, listed, conf)
+		if err != nil {
+			pkg.Error = err.Error()
+		}
+		idx.Packages = append(idx.Packages, pkg)
+		progress(listed.ImportPath, false, err)
+	}
+	return idx, nil
+}
+
+// indexPackage runs each top-level test of the package
+// the returned package is never nil so that an error can be recorded in it
+func indexPackage(root string, listed tester.ModulePackage, conf tester.Config) (*Package, error) {
+	pkg := &Package{
+		ImportPath: listed.ImportPath,
+		Hash:       listed.Hash,
+		Blocks:     []cover.Block{},
+		Funcs:      []Func{},
+		Tests:      []Test{},
+	}
+	rel, err := filepath.Rel(root, listed.Dir)
+	if err != nil {
+		return pkg, err
+	}
+	pkg.Dir = filepath.ToSlash(rel)
+
+	funcs, err := finder.Funcs(listed.Dir, listed.GoFiles)
+	if err != nil {
+		return pkg, fmt.Errorf("error parsing %s: %w", listed.ImportPath, err)
+	}
+	for i := range funcs {
+		funcs[i].File = path.Join(pkg.Dir, funcs[i].File)
+	}
+	pkg.Funcs = funcs
+	if !listed.HasTests {
+		return pkg, nil
+	}
+
+	t, err := tester.NewPackage(listed.Dir, conf)
+	if err != nil {
+		return pkg, err
+	}
+	profiles, err := t.Profiles()
+	if err != nil {
+		return pkg, err
+	}
+	positions, err := finder.PackageTests(listed.Dir)
+	if err != nil {
+		return pkg, fmt.Errorf("error finding tests in %s: %w", listed.Dir, err)
+	}
+
+	byName := make(map[string]tester.TestProfile, len(profiles))
+	for _, p := range profiles {
+		byName[p.Name] = p
+	}
+	m := tester.NewMatrix(profiles)
+	for _, b := range m.Blocks {
+		b.File = path.Join(pkg.Dir, b.File)
+		pkg.Blocks = append(pkg.Blocks, b)
+	}
+	for i, name := range m.Tests {
+		test := Test{
+			Name:    name,
+			Kind:    byName[name].Kind,
+			Elapsed: byName[name].Elapsed,
+			Status:  byName[name].Status,
+			Blocks:  []int{},
+			Counts:  []int{},
+		}
+		if pos, ok := positions[name]; ok {
+			if rel, err := filepath.Rel(root, pos.File); err == nil {
+				test.File = filepath.ToSlash(rel)
+			}
+			test.Line = pos.Line
+		}
+		for j, count := range m.Counts[i] {
+			if count != 0 {
+				test.Blocks = append(test.Blocks, j)
+				test.Counts = append(test.Counts, count)
+			}
+		}
+		pkg.Tests = append(pkg.Tests, test)
+	}
+	return pkg, nil
+}
@@ -0,0 +1,158 @@
+package index
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/ShawnROGrady/go-find-tests/tester"
+)
+
+const (
+	indexSource = `package idx
+
+func Sign(n int) int {
+	if n < 0 {
+		return -1
+	}
+	return 1
+}
+`
+	indexTestSource = `package idx
+
+import "testing"
+
+func TestNegative(t *testing.T) {
+	Sign(-1)
+}
+
+func TestPositive(t *testing.T) {
+	Sign(1)
+}
+`
+	// depends on the root package
+	indexSubSource = `package sub
+
+import "example.com/idx"
+
+type Counter struct{ n int }
+
+func (c *Counter) Inc() {
+	c.n += idx.Sign(1)
+}
+`
+	indexSubTestSource = `package sub
+
+import "testing"
+
+func TestInc(t *testing.T) {
+	var c Counter
+	c.Inc()
+}
+`
+)
+
+func TestBuild(t *testing.T) {
+	dir, err := ioutil.TempDir("", "index")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	write := func(name, src string) {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0755); err != nil {
+			t.Fatalf("Unexpected error creating directory of %s: %s", name, err)
+		}
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(src), 0644); err != nil {
+			t.Fatalf("Unexpected error writing %s: %s", name, err)
+		}
+	}
+	write("go.mod", "module example.com/idx\n")
+	write("sign.go", indexSource)
+	write("sign_test.go", indexTestSource)
+	write("sub/sub.go", indexSubSource)
+	write("sub/sub_test.go", indexSubTestSource)
+
+	var prev *Index
+	// returns the packages which were re-indexed
+	build := func() string {
+		t.Helper()
+		reindexed := []string{}
+		idx, err := Build(dir, tester.Config{}, prev, func(pkg string, reused bool, err error) {
+			if err != nil {
+				t.Errorf("Unexpected error indexing %s: %s", pkg, err)
+			}
+			if !reused {
+				reindexed = append(reindexed, pkg)
+			}
+		})
+		if err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		prev = idx
+		sort.Strings(reindexed)
+		return fmt.Sprint(reindexed)
+	}
+
+	steps := []struct {
+		name              string
+		change            func()
+		expectedReindexed string
+	}{
+		{
+			name:              "initial",
+			change:            func() {},
+			expectedReindexed: "[example.com/idx example.com/idx/sub]",
+		},
+		{
+			name:              "unchanged",
+			change:            func() {},
+			expectedReindexed: "[]",
+		},
+		{
+			name: "dependent_test_changed",
+			change: func() {
+				write("sub/sub_test.go", indexSubTestSource+"\nfunc TestNothing(t *testing.T) {}\n")
+			},
+			expectedReindexed: "[example.com/idx/sub]",
+		},
+		{
+			name: "dependency_changed",
+			change: func() {
+				write("sign.go", indexSource+"\nfunc Zero() int { return 0 }\n")
+			},
+			expectedReindexed: "[example.com/idx example.com/idx/sub]",
+		},
+	}
+	for _, step := range steps {
+		step.change()
+		if reindexed := build(); reindexed != step.expectedReindexed {
+			t.Errorf("Unexpected re-indexed packages after %s (expected = %s, actual = %s)", step.name, step.expectedReindexed, reindexed)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := prev.Write(&buf); err != nil {
+		t.Fatalf("Unexpected error writing index: %s", err)
+	}
+	idx, err := Read(&buf)
+	if err != nil {
+		t.Fatalf("Unexpected error reading index: %s", err)
+	}
+
+	expectedHits := []Hit{{Package: "example.com/idx", Test: "TestNegative", Count: 1}}
+	if hits := idx.Position("sign.go", 5, 0); fmt.Sprint(hits) != fmt.Sprint(expectedHits) {
+		t.Errorf("Unexpected hits (expected = %v, actual = %v)", expectedHits, hits)
+	}
+	expectedHits = []Hit{{Package: "example.com/idx/sub", Test: "TestInc", Count: 1}}
+	if hits, err := idx.Symbol("sub.Counter.Inc"); err != nil || fmt.Sprint(hits) != fmt.Sprint(expectedHits) {
+		t.Errorf("Unexpected hits (expected = %v, actual = %v, err = %v)", expectedHits, hits, err)
+	}
+	if tests := idx.Packages[1].Tests; len(tests) != 2 || tests[0].File != "sub/sub_test.go" || tests[0].Line != 5 {
+		t.Errorf("Unexpected tests of sub: %+v", tests)
+	}
+}
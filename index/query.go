@@ -0,0 +1,164 @@
+package index
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/ShawnROGrady/go-find-tests/cover"
+)
+
+// ErrNotFound is returned when a queried symbol or test is not in the index
+var ErrNotFound = errors.New("not found in index")
+
+// Hit is a test which executed a queried statement
+type Hit struct {
+	Package string `json:"package"`
+	Test    string `json:"test"`
+	Count   int    `json:"count"` // total number of times the test executed the queried blocks
+}
+
+// Coverage is the blocks executed by a single test
+type Coverage struct {
+	Package string        `json:"package"`
+	Test    string        `json:"test"`
+	Blocks  []cover.Block `json:"blocks"` // Count is the number of times the test executed the block
+}
+
+// Position returns the tests which cover the statement at the position in the file
+// a col of 0 means no column was specified, in which case any covered block on the line counts
+func (idx *Index) Position(file string, line, col int) []Hit {
+	return idx.hits(func(pkg *Package) []int {
+		matched := []int{}
+		for j, b := range pkg.Blocks {
+			if b.File != file {
+				continue
+			}
+			if col == 0 && b.StartLine <= line && b.EndLine >= line {
+				matched = append(matched, j)
+			} else if col != 0 && b.Contains(line, col) {
+				// consistent with cover.Profile, only the first block containing the position counts
+				return []int{j}
+			}
+		}
+		return matched
+	}, file)
+}
+
+// Lines returns the tests which cover any statement within the inclusive range of lines of the file
+func (idx *Index) Lines(file string, start, end int) []Hit {
+	return idx.hits(func(pkg *Package) []int {
+		return pkg.linesBlocks(file, start, end)
+	}, file)
+}
+
+// Symbol returns the tests which cover any statement of the functions or methods matching the name
+//
+// the name may be unqualified (e.g. 'New' or 'Profile.Covers'), or qualified by either the last element
+// of the import path (e.g. 'cover.New') or the full import path of the package.
+// methods with pointer receivers match with and without the '(*T)' form
+func (idx *Index) Symbol(name string) ([]Hit, error) {
+	var (
+		query = normalizeFunc(name)
+		found bool
+	)
+	hits := idx.hits(func(pkg *Package) []int {
+		var (
+			matched = []int{}
+			base    = path.Base(pkg.ImportPath)
+		)
+		for _, fn := range pkg.Funcs {
+			fnName := normalizeFunc(fn.Name)
+			if query != fnName && query != base+"."+fnName && query != pkg.ImportPath+"."+fnName {
+				continue
+			}
+			found = true
+			matched = append(matched, pkg.linesBlocks(fn.File, fn.StartLine, fn.EndLine)...)
+		}
+		return matched
+	}, "")
+	if !found {
+		return nil, fmt.Errorf("symbol '%s': %w", name, ErrNotFound)
+	}
+	return hits, nil
+}
+
+// Test returns the blocks executed by each test with the name
+// the name may be qualified by the import path of its package, e.g. 'github.com/org/repo/pkg.TestName'
+func (idx *Index) Test(name string) ([]Coverage, error) {
+	pkgPath, testName := "", name
+	if i := strings.LastIndex(name, "."); i != -1 {
+		pkgPath, testName = name[:i], name[i+1:]
+	}
+
+	coverage := []Coverage{}
+	for _, pkg := range idx.Packages {
+		if pkgPath != "" && pkgPath != pkg.ImportPath {
+			continue
+		}
+		for _, test := range pkg.Tests {
+			if test.Name != testName {
+				continue
+			}
+			c := Coverage{Package: pkg.ImportPath, Test: test.Name, Blocks: make([]cover.Block, len(test.Blocks))}
+			for k, j := range test.Blocks {
+				c.Blocks[k] = pkg.Blocks[j]
+				c.Blocks[k].Count = test.Counts[k]
+			}
+			coverage = append(coverage, c)
+		}
+	}
+	if len(coverage) == 0 {
+		return nil, fmt.Errorf("test '%s': %w", name, ErrNotFound)
+	}
+	return coverage, nil
+}
+
+// hits returns the tests which executed any of the blocks matched in each package, ordered by package and test
+// if file is set only the package containing the file is searched
+func (idx *Index) hits(match func(pkg *Package) []int, file string) []Hit {
+	hits := []Hit{}
+	for _, pkg := range idx.Packages {
+		if file != "" && path.Dir(file) != pkg.Dir {
+			continue
+		}
+		matched := match(pkg)
+		if len(matched) == 0 {
+			continue
+		}
+		blocks := make(map[int]bool, len(matched))
+		for _, j := range matched {
+			blocks[j] = true
+		}
+
+		for _, test := range pkg.Tests {
+			var count int
+			for k, j := range test.Blocks {
+				if blocks[j] {
+					count += test.Counts[k]
+				}
+			}
+			if count != 0 {
+				hits = append(hits, Hit{Package: pkg.ImportPath, Test: test.Name, Count: count})
+			}
+		}
+	}
+	return hits
+}
+
+// linesBlocks returns the indexes of the blocks of the file which overlap the inclusive range of lines
+func (pkg *Package) linesBlocks(file string, start, end int) []int {
+	matched := []int{}
+	for j, b := range pkg.Blocks {
+		if b.File == file && b.StartLine <= end && b.EndLine >= start {
+			matched = append(matched, j)
+		}
+	}
+	return matched
+}
+
+// normalizeFunc removes the pointer from the receiver of a method, i.e. '(*T).M' becomes 'T.M'
+func normalizeFunc(name string) string {
+	return strings.NewReplacer("(*", "", ")", "").Replace(name)
+}
@@ -0,0 +1,227 @@
+package index
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/ShawnROGrady/go-find-tests/cover"
+)
+
+// queryIndex has two packages, the root package and 'sub'
+// sign.go:
+//
+//	3 func sign(n int) int {
+//	4 	if n < 0 {
+//	5 		return -1
+//	6 	}
+//	7 	return 1
+//	8 }
+var queryIndex = &Index{
+	Version: Version,
+	Module:  "example.com/mod",
+	Packages: []*Package{
+		{
+			ImportPath: "example.com/mod",
+			Dir:        ".",
+			Blocks: []cover.Block{
+				{File: "sign.go", StartLine: 3, StartCol: 23, EndLine: 4, EndCol: 11, NumStmt: 1, Count: 3},
+				{File: "sign.go", StartLine: 4, StartCol: 11, EndLine: 6, EndCol: 3, NumStmt: 1, Count: 1},
+				{File: "sign.go", StartLine: 7, StartCol: 2, EndLine: 7, EndCol: 10, NumStmt: 1, Count: 2},
+			},
+			Funcs: []Func{{Name: "sign", File: "sign.go", StartLine: 3, EndLine: 8}},
+			Tests: []Test{
+				{Name: "TestNegative", Blocks: []int{0, 1}, Counts: []int{1, 1}},
+				{Name: "TestPositive", Blocks: []int{0, 2}, Counts: []int{2, 2}},
+			},
+		},
+		{
+			ImportPath: "example.com/mod/sub",
+			Dir:        "sub",
+			Blocks: []cover.Block{
+				{File: "sub/sub.go", StartLine: 5, StartCol: 30, EndLine: 7, EndCol: 2, NumStmt: 1, Count: 1},
+			},
+			Funcs: []Func{{Name: "(*Counter).Inc", File: "sub/sub.go", StartLine: 5, EndLine: 7}},
+			Tests: []Test{
+				{Name: "TestInc", Blocks: []int{0}, Counts: []int{1}},
+				{Name: "TestPositive", Blocks: []int{}, Counts: []int{}},
+			},
+		},
+	},
+}
+
+var positionTests = map[string]struct {
+	file         string
+	line, col    int
+	expectedHits []Hit
+}{
+	"line_covered_by_one_test": {
+		file: "sign.go", line: 5,
+		expectedHits: []Hit{{Package: "example.com/mod", Test: "TestNegative", Count: 1}},
+	},
+	"line_spanning_two_blocks": {
+		file: "sign.go", line: 4,
+		expectedHits: []Hit{
+			{Package: "example.com/mod", Test: "TestNegative", Count: 2},
+			{Package: "example.com/mod", Test: "TestPositive", Count: 2},
+		},
+	},
+	"column_in_first_block": {
+		file: "sign.go", line: 4, col: 5,
+		expectedHits: []Hit{
+			{Package: "example.com/mod", Test: "TestNegative", Count: 1},
+			{Package: "example.com/mod", Test: "TestPositive", Count: 2},
+		},
+	},
+	"column_in_second_block": {
+		file: "sign.go", line: 4, col: 12,
+		expectedHits: []Hit{{Package: "example.com/mod", Test: "TestNegative", Count: 1}},
+	},
+	"subpackage": {
+		file: "sub/sub.go", line: 6,
+		expectedHits: []Hit{{Package: "example.com/mod/sub", Test: "TestInc", Count: 1}},
+	},
+	"no_block": {
+		file: "sign.go", line: 1,
+		expectedHits: []Hit{},
+	},
+	"unknown_file": {
+		file: "other.go", line: 4,
+		expectedHits: []Hit{},
+	},
+}
+
+func TestPosition(t *testing.T) {
+	for testName, test := range positionTests {
+		test := test
+		t.Run(testName, func(t *testing.T) {
+			hits := queryIndex.Position(test.file, test.line, test.col)
+			if fmt.Sprint(hits) != fmt.Sprint(test.expectedHits) {
+				t.Errorf("Unexpected hits (expected = %v, actual = %v)", test.expectedHits, hits)
+			}
+		})
+	}
+}
+
+func TestLines(t *testing.T) {
+	hits := queryIndex.Lines("sign.go", 5, 7)
+	expected := []Hit{
+		{Package: "example.com/mod", Test: "TestNegative", Count: 1},
+		{Package: "example.com/mod", Test: "TestPositive", Count: 2},
+	}
+	if fmt.Sprint(hits) != fmt.Sprint(expected) {
+		t.Errorf("Unexpected hits (expected = %v, actual = %v)", expected, hits)
+	}
+}
+
+var symbolTests = map[string]struct {
+	symbol        string
+	expectedHits  []Hit
+	expectedError error
+}{
+	"unqualified": {
+		symbol: "sign",
+		expectedHits: []Hit{
+			{Package: "example.com/mod", Test: "TestNegative", Count: 2},
+			{Package: "example.com/mod", Test: "TestPositive", Count: 4},
+		},
+	},
+	"pointer_method": {
+		symbol:       "(*Counter).Inc",
+		expectedHits: []Hit{{Package: "example.com/mod/sub", Test: "TestInc", Count: 1}},
+	},
+	"method_without_pointer": {
+		symbol:       "Counter.Inc",
+		expectedHits: []Hit{{Package: "example.com/mod/sub", Test: "TestInc", Count: 1}},
+	},
+	"package_name_qualified": {
+		symbol:       "sub.Counter.Inc",
+		expectedHits: []Hit{{Package: "example.com/mod/sub", Test: "TestInc", Count: 1}},
+	},
+	"import_path_qualified": {
+		symbol:       "example.com/mod/sub.(*Counter).Inc",
+		expectedHits: []Hit{{Package: "example.com/mod/sub", Test: "TestInc", Count: 1}},
+	},
+	"unknown": {
+		symbol:        "Counter.Dec",
+		expectedError: ErrNotFound,
+	},
+}
+
+func TestSymbol(t *testing.T) {
+	for testName, test := range symbolTests {
+		test := test
+		t.Run(testName, func(t *testing.T) {
+			hits, err := queryIndex.Symbol(test.symbol)
+			if test.expectedError != nil {
+				if !errors.Is(err, test.expectedError) {
+					t.Fatalf("Unexpected error (expected = %v, actual = %v)", test.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if fmt.Sprint(hits) != fmt.Sprint(test.expectedHits) {
+				t.Errorf("Unexpected hits (expected = %v, actual = %v)", test.expectedHits, hits)
+			}
+		})
+	}
+}
+
+var testCoverageTests = map[string]struct {
+	name             string
+	expectedPackages []string
+	expectedBlocks   [][]cover.Block
+	expectedError    error
+}{
+	"unqualified_in_multiple_packages": {
+		name:             "TestPositive",
+		expectedPackages: []string{"example.com/mod", "example.com/mod/sub"},
+		expectedBlocks: [][]cover.Block{
+			{
+				{File: "sign.go", StartLine: 3, StartCol: 23, EndLine: 4, EndCol: 11, NumStmt: 1, Count: 2},
+				{File: "sign.go", StartLine: 7, StartCol: 2, EndLine: 7, EndCol: 10, NumStmt: 1, Count: 2},
+			},
+			{},
+		},
+	},
+	"qualified": {
+		name:             "example.com/mod/sub.TestPositive",
+		expectedPackages: []string{"example.com/mod/sub"},
+		expectedBlocks:   [][]cover.Block{{}},
+	},
+	"unknown": {
+		name:          "TestZero",
+		expectedError: ErrNotFound,
+	},
+}
+
+func TestTest(t *testing.T) {
+	for testName, test := range testCoverageTests {
+		test := test
+		t.Run(testName, func(t *testing.T) {
+			coverage, err := queryIndex.Test(test.name)
+			if test.expectedError != nil {
+				if !errors.Is(err, test.expectedError) {
+					t.Fatalf("Unexpected error (expected = %v, actual = %v)", test.expectedError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if len(coverage) != len(test.expectedPackages) {
+				t.Fatalf("Unexpected number of tests (expected = %d, actual = %d)", len(test.expectedPackages), len(coverage))
+			}
+			for i := range coverage {
+				if coverage[i].Package != test.expectedPackages[i] {
+					t.Errorf("Unexpected package %d (expected = %s, actual = %s)", i, test.expectedPackages[i], coverage[i].Package)
+				}
+				if fmt.Sprint(coverage[i].Blocks) != fmt.Sprint(test.expectedBlocks[i]) {
+					t.Errorf("Unexpected blocks %d (expected = %v, actual = %v)", i, test.expectedBlocks[i], coverage[i].Blocks)
+				}
+			}
+		})
+	}
+}
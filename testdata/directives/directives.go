@@ -0,0 +1,8 @@
+package directives
+
+import "time"
+
+func wait(d time.Duration) bool {
+	time.Sleep(d)
+	return true
+}
@@ -0,0 +1,30 @@
+package directives
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFast(t *testing.T) {
+	wait(0)
+}
+
+// TestEndToEnd fails if it is ever run
+//
+//gofindtests:skip
+func TestEndToEnd(t *testing.T) {
+	wait(0)
+	t.Fatal("skipped test was run")
+}
+
+//gofindtests:timeout 100ms
+func TestTimedOut(t *testing.T) {
+	wait(5 * time.Second)
+}
+
+// TestExtended takes longer than the configured timeout
+//
+//gofindtests:timeout 1m
+func TestExtended(t *testing.T) {
+	wait(500 * time.Millisecond)
+}
@@ -0,0 +1,9 @@
+package exact
+
+// used to test that tests are run by their exact names
+func classify(n int) string {
+	if n < 0 {
+		return "negative"
+	}
+	return "non-negative"
+}
@@ -0,0 +1,35 @@
+package exact
+
+import "testing"
+
+// TestClassify is a prefix of the other tests, so an unanchored '-run TestClassify' runs them all
+func TestClassify(t *testing.T) {
+	if c := classify(1); c != "non-negative" {
+		t.Errorf("Unexpected classify(1) (expected = non-negative, actual = %s)", c)
+	}
+}
+
+func TestClassifyNegative(t *testing.T) {
+	if c := classify(-1); c != "negative" {
+		t.Errorf("Unexpected classify(-1) (expected = negative, actual = %s)", c)
+	}
+}
+
+// subtest names contain regular expression metacharacters which don't match themselves
+func TestClassifyCases(t *testing.T) {
+	for _, test := range []struct {
+		name     string
+		n        int
+		expected string
+	}{
+		{name: "f(-1)", n: -1, expected: "negative"},
+		{name: "f(1)", n: 1, expected: "non-negative"},
+		{name: "f[0]", n: 0, expected: "non-negative"},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			if c := classify(test.n); c != test.expected {
+				t.Errorf("Unexpected classify(%d) (expected = %s, actual = %s)", test.n, test.expected, c)
+			}
+		})
+	}
+}
@@ -0,0 +1,8 @@
+package filter
+
+import "strconv"
+
+func parse(s string) (int, bool) {
+	n, err := strconv.Atoi(s)
+	return n, err == nil
+}
@@ -0,0 +1,30 @@
+package filter
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := map[string]struct {
+		input string
+		ok    bool
+	}{
+		"valid_positive": {input: "1", ok: true},
+		"valid_negative": {input: "-1", ok: true},
+		"invalid_empty":  {input: "", ok: false},
+	}
+	for name, test := range tests {
+		test := test
+		t.Run(name, func(t *testing.T) {
+			if _, ok := parse(test.input); ok != test.ok {
+				t.Errorf("unexpected ok for '%s'", test.input)
+			}
+		})
+	}
+}
+
+func TestParseAll(t *testing.T) {
+	for _, s := range []string{"1", "2"} {
+		if _, ok := parse(s); !ok {
+			t.Errorf("failed to parse '%s'", s)
+		}
+	}
+}
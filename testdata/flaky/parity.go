@@ -0,0 +1,9 @@
+package flaky
+
+// used to test detecting nondeterministic coverage
+func parity(n int) string {
+	if n%2 == 0 {
+		return "even"
+	}
+	return "odd"
+}
@@ -0,0 +1,27 @@
+package flaky
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+// TestAlternating alternates between the even and odd branches of parity on each run
+// runs are counted in the file named by $FLAKY_COUNTER, if unset only the even branch is covered
+func TestAlternating(t *testing.T) {
+	var n int
+	if path := os.Getenv("FLAKY_COUNTER"); path != "" {
+		b, _ := ioutil.ReadFile(path)
+		n = len(b)
+		if err := ioutil.WriteFile(path, append(b, '.'), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	parity(n)
+}
+
+func TestEven(t *testing.T) {
+	if parity(2) != "even" {
+		t.Errorf("Unexpected parity(2) (expected = even)")
+	}
+}
@@ -0,0 +1,11 @@
+package initonly
+
+var table = build()
+
+func build() map[string]int {
+	return map[string]int{"a": 1}
+}
+
+func Lookup(k string) int {
+	return table[k]
+}
@@ -0,0 +1,9 @@
+package initonly
+
+import "testing"
+
+func TestLookup(t *testing.T) {
+	if Lookup("a") != 1 {
+		t.Error("Unexpected value")
+	}
+}
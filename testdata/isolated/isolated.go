@@ -0,0 +1,6 @@
+package isolated
+
+// used to test running each test in its own environment
+func touch() bool {
+	return true
+}
@@ -0,0 +1,44 @@
+package isolated
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+// each test fails if its state is shared with another run, so they can only pass when isolated
+
+func claim(t *testing.T, dir string) {
+	t.Helper()
+	f, err := os.OpenFile(dir+"/claimed", os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatalf("%s is shared: %s", dir, err)
+	}
+	f.Close()
+}
+
+func TestHome(t *testing.T) {
+	touch()
+	claim(t, os.Getenv("HOME"))
+	claim(t, os.Getenv("XDG_CONFIG_HOME"))
+}
+
+func TestTempDir(t *testing.T) {
+	touch()
+	claim(t, os.TempDir())
+}
+
+func TestWorkingDir(t *testing.T) {
+	touch()
+	claim(t, ".")
+	if b, err := ioutil.ReadFile("testdata/input.txt"); err != nil || string(b) != "fixture input\n" {
+		t.Fatalf("Unexpected testdata/input.txt: %q, %v", b, err)
+	}
+}
+
+func TestEnv(t *testing.T) {
+	touch()
+	if os.Getenv("ISOLATED_EXTRA") != "value" {
+		t.Fatalf("Unexpected ISOLATED_EXTRA: %q", os.Getenv("ISOLATED_EXTRA"))
+	}
+}
@@ -0,0 +1,32 @@
+package kinds
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestReverseEmpty(t *testing.T) {
+	if reverse("") != "" {
+		t.Errorf("Unexpected reverse('') (expected = '')")
+	}
+}
+
+func Example_reverse() {
+	fmt.Println(reverse("hello"))
+	// Output: olleh
+}
+
+func BenchmarkReverse(b *testing.B) {
+	for n := 0; n < b.N; n++ {
+		reverse("hello")
+	}
+}
+
+func FuzzReverse(f *testing.F) {
+	f.Add("hello")
+	f.Fuzz(func(t *testing.T, s string) {
+		if reverse(reverse(s)) != s {
+			t.Errorf("Unexpected reverse(reverse('%s'))", s)
+		}
+	})
+}
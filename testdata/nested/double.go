@@ -0,0 +1,6 @@
+package nested
+
+// used to test resolving packages within a nested module
+func double(i int) int {
+	return i * 2
+}
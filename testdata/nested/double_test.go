@@ -0,0 +1,9 @@
+package nested
+
+import "testing"
+
+func TestDouble(t *testing.T) {
+	if double(2) != 4 {
+		t.Errorf("Unexpected double(2) (expected = 4)")
+	}
+}
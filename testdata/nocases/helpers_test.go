@@ -0,0 +1,7 @@
+package nocases
+
+// a test file which only declares helpers still builds a test binary
+
+func doubled(n int) int {
+	return Double(n)
+}
@@ -0,0 +1,5 @@
+package nocases
+
+func Double(n int) int {
+	return 2 * n
+}
@@ -0,0 +1,14 @@
+package notests
+
+var table = build()
+
+func build() map[string]int {
+	return map[string]int{"a": 1}
+}
+
+func Lookup(k string) int {
+	if v, ok := table[k]; ok {
+		return v
+	}
+	return -1
+}
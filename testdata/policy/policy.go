@@ -0,0 +1,5 @@
+package policy
+
+func touch() bool {
+	return true
+}
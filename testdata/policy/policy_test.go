@@ -0,0 +1,83 @@
+package policy
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// each test marks itself as running in the directory named by $POLICY_DIR
+// and fails if a test it conflicts with is running at the same time
+
+func exclusive(t *testing.T, conflicts func(test string) bool) {
+	t.Helper()
+	touch()
+	dir := os.Getenv("POLICY_DIR")
+	if dir == "" {
+		return
+	}
+	running := filepath.Join(dir, t.Name())
+	if err := ioutil.WriteFile(running, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(running)
+
+	time.Sleep(50 * time.Millisecond)
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, info := range infos {
+		if info.Name() != t.Name() && conflicts(info.Name()) {
+			t.Errorf("%s is running concurrently", info.Name())
+		}
+	}
+}
+
+func everything(string) bool { return true }
+
+func none(string) bool { return false }
+
+func prefixed(prefix string) func(string) bool {
+	return func(test string) bool { return strings.HasPrefix(test, prefix) }
+}
+
+//gofindtests:serial
+func TestSerial(t *testing.T) {
+	exclusive(t, everything)
+}
+
+// TestGlobal is run serially by the configured patterns
+func TestGlobal(t *testing.T) {
+	exclusive(t, everything)
+}
+
+//gofindtests:group db
+func TestDBRead(t *testing.T) {
+	exclusive(t, prefixed("TestDB"))
+}
+
+//gofindtests:group db
+func TestDBWrite(t *testing.T) {
+	exclusive(t, prefixed("TestDB"))
+}
+
+// TestCacheGet and TestCachePut are grouped by the configured patterns
+func TestCacheGet(t *testing.T) {
+	exclusive(t, prefixed("TestCache"))
+}
+
+func TestCachePut(t *testing.T) {
+	exclusive(t, prefixed("TestCache"))
+}
+
+func TestUnitA(t *testing.T) {
+	exclusive(t, none)
+}
+
+func TestUnitB(t *testing.T) {
+	exclusive(t, none)
+}
@@ -0,0 +1,11 @@
+package redundant
+
+func sign(n int) string {
+	if n < 0 {
+		return "negative"
+	}
+	if n == 0 {
+		return "zero"
+	}
+	return "positive"
+}
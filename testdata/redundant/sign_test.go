@@ -0,0 +1,41 @@
+package redundant
+
+import "testing"
+
+var signTests = map[string]struct {
+	input    int
+	expected string
+}{
+	"negative": {
+		input:    -1,
+		expected: "negative",
+	},
+	"zero": {
+		input:    0,
+		expected: "zero",
+	},
+	"positive": {
+		input:    1,
+		expected: "positive",
+	},
+	"also_positive": {
+		input:    2,
+		expected: "positive",
+	},
+}
+
+func TestSign(t *testing.T) {
+	for testName, testCase := range signTests {
+		t.Run(testName, func(t *testing.T) {
+			if actual := sign(testCase.input); actual != testCase.expected {
+				t.Errorf("unexpected sign(%d) [expected = %s, actual = %s]", testCase.input, testCase.expected, actual)
+			}
+		})
+	}
+}
+
+func TestPositive(t *testing.T) {
+	if actual := sign(5); actual != "positive" {
+		t.Errorf("unexpected sign(5) [expected = positive, actual = %s]", actual)
+	}
+}
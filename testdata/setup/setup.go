@@ -0,0 +1,15 @@
+package setup
+
+var table = build()
+
+func build() map[string]int {
+	return map[string]int{"a": 1}
+}
+
+func lookup(key string) int {
+	return table[key]
+}
+
+func other() int {
+	return 2
+}
@@ -0,0 +1,23 @@
+package setup
+
+import (
+	"os"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	lookup("a")
+	os.Exit(m.Run())
+}
+
+func TestLookup(t *testing.T) {
+	lookup("a")
+}
+
+func TestRebuild(t *testing.T) {
+	build()
+}
+
+func TestOther(t *testing.T) {
+	other()
+}
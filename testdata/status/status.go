@@ -0,0 +1,5 @@
+package status
+
+func check(n int) bool {
+	return n > 0
+}
@@ -0,0 +1,28 @@
+package status
+
+import "testing"
+
+// each test covers check before its outcome is decided
+
+func TestStatus(t *testing.T) {
+	t.Run("passes", func(t *testing.T) {
+		check(1)
+	})
+	t.Run("fails", func(t *testing.T) {
+		check(2)
+		t.Error("failed after covering")
+	})
+	t.Run("skips", func(t *testing.T) {
+		check(3)
+		t.Skip("skipped after covering")
+	})
+}
+
+func TestSkipped(t *testing.T) {
+	check(4)
+	t.Skip("skipped after covering")
+}
+
+func TestPasses(t *testing.T) {
+	check(5)
+}
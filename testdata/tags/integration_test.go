@@ -0,0 +1,10 @@
+//go:build integration
+// +build integration
+
+package tags
+
+import "testing"
+
+func TestIntegration(t *testing.T) {
+	touch()
+}
@@ -0,0 +1,5 @@
+package tags
+
+func touch() bool {
+	return true
+}
@@ -0,0 +1,7 @@
+package tags
+
+import "testing"
+
+func TestUnit(t *testing.T) {
+	touch()
+}
@@ -0,0 +1,9 @@
+package timeout
+
+import "time"
+
+// this will be used to test behaviour when a test exceeds the timeout
+func slow() int {
+	time.Sleep(5 * time.Second)
+	return 1
+}
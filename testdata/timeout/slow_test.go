@@ -0,0 +1,9 @@
+package timeout
+
+import "testing"
+
+func TestSlow(t *testing.T) {
+	if slow() != 1 {
+		t.Errorf("Unexpected slow() (expected = 1)")
+	}
+}
@@ -0,0 +1,12 @@
+package uncovered
+
+func Abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func Unused() string {
+	return "unused"
+}
@@ -0,0 +1,9 @@
+package uncovered
+
+import "testing"
+
+func TestAbs(t *testing.T) {
+	if Abs(1) != 1 {
+		t.Error("unexpected absolute value")
+	}
+}
@@ -0,0 +1,13 @@
+package a
+
+// Sign returns the sign of the provided int
+// used to test searching for tests across workspace modules
+func Sign(i int) int {
+	switch {
+	case i < 0:
+		return -1
+	case i > 0:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,9 @@
+package a
+
+import "testing"
+
+func TestSignPositive(t *testing.T) {
+	if Sign(5) != 1 {
+		t.Errorf("Unexpected Sign(5) (expected = 1)")
+	}
+}
@@ -0,0 +1,8 @@
+package b
+
+import "example.com/a"
+
+// Abs returns the absolute value of the provided int
+func Abs(i int) int {
+	return i * a.Sign(i)
+}
@@ -0,0 +1,15 @@
+package b
+
+import "testing"
+
+func TestAbsNegative(t *testing.T) {
+	if Abs(-5) != 5 {
+		t.Errorf("Unexpected Abs(-5) (expected = 5)")
+	}
+}
+
+func TestAbsZero(t *testing.T) {
+	if Abs(0) != 0 {
+		t.Errorf("Unexpected Abs(0) (expected = 0)")
+	}
+}
@@ -0,0 +1,6 @@
+#!/bin/sh
+# runs the test binary with WRAPPED set to the first argument, as 'go test -exec "./wrap.sh value"' would
+WRAPPED="$1"
+export WRAPPED
+shift
+exec "$@"
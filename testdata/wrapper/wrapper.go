@@ -0,0 +1,10 @@
+package wrapper
+
+import "os"
+
+func wrappedBy() string {
+	if v := os.Getenv("WRAPPED"); v != "" {
+		return v
+	}
+	return "none"
+}
@@ -0,0 +1,7 @@
+package wrapper
+
+import "testing"
+
+func TestWrappedBy(t *testing.T) {
+	t.Logf("wrapped by %s", wrappedBy())
+}
@@ -1,15 +1,18 @@
 package tester
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/ShawnROGrady/go-find-tests/cover"
 	"golang.org/x/sync/errgroup"
 )
 
 type coverFinder interface {
-	coveringTests(t *Tester, testBin, outputDir string, allTests []string, includeSubtests bool) ([]string, error)
+	coveringTests(t *Tester, testBin, outputDir string, allTests []string, includeSubtests bool) ([]Result, error)
+	profiles(t *Tester, testBin, outputDir string, allTests []string) ([]TestProfile, error)
 }
 
 /*
@@ -19,55 +22,60 @@ as well as establishing a baseline for benchmarks
 */
 type sequentialFinder struct{}
 
-func (s sequentialFinder) coveringTests(t *Tester, testBin, outputDir string, allTests []string, includeSubtests bool) ([]string, error) {
-	coveredBy := []string{}
+func (s sequentialFinder) coveringTests(t *Tester, testBin, outputDir string, allTests []string, includeSubtests bool) ([]Result, error) {
+	coveredBy := []Result{}
 	for i := range allTests {
-		coverout, stdout, err := t.runCompiledTest(allTests[i], testBin, outputDir)
+		results, subTests, err := t.coveringRun(allTests[i], testBin, outputDir, includeSubtests)
 		if err != nil {
-			return []string{}, fmt.Errorf("error running test '%s': %s", allTests[i], err)
+			return []Result{}, err
+		}
+		coveredBy = append(coveredBy, results...)
+		if len(subTests) == 0 {
+			continue
 		}
 
-		prof, err := cover.New(coverout)
+		coveringSubs, err := s.coveringTests(t, testBin, outputDir, subTests, false)
 		if err != nil {
-			return []string{}, fmt.Errorf("error parsing coverage output: %s", err)
-		}
-		if err := coverout.Close(); err != nil {
-			return []string{}, err
+			return coveredBy, err
 		}
+		coveredBy = append(coveredBy, coveringSubs...)
+	}
+	return coveredBy, nil
+}
 
-		if prof.Covers(t.testPos.file, t.testPos.line, t.testPos.col) {
-			coveredBy = append(coveredBy, allTests[i])
-			if includeSubtests {
-				subTests, err := subtests(stdout)
-				if err != nil {
-					return []string{}, fmt.Errorf("error finding subtests: %s", err)
-				}
-				if len(subTests) == 0 {
-					continue
-				}
-				if len(subTests) == 1 {
-					coveredBy = append(coveredBy, subTests[0])
-					continue
-				}
-				coveringSubs, err := s.coveringTests(t, testBin, outputDir, subTests, false)
-				if err != nil {
-					return coveredBy, err
-				}
-				coveredBy = append(coveredBy, coveringSubs...)
-			}
+func (s sequentialFinder) profiles(t *Tester, testBin, outputDir string, allTests []string) ([]TestProfile, error) {
+	profiles := make([]TestProfile, len(allTests))
+	for i := range allTests {
+		var err error
+		profiles[i], err = t.profileRun(allTests[i], testBin, outputDir)
+		if err != nil {
+			return []TestProfile{}, err
 		}
 	}
-	return coveredBy, nil
+	return profiles, nil
 }
 
 // errGroupFinder runs each test in a separate go routine managed by an error group
-type errGroupFinder struct{}
+type errGroupFinder struct {
+	gate gate // if nil tests are run without restriction
+}
+
+// gate is called before running a test and returns the function to call once it has finished
+type gate func(t *Tester, testName string) (release func())
 
-func (e errGroupFinder) coveringTests(t *Tester, testBin, outputDir string, allTests []string, includeSubtests bool) ([]string, error) {
+// enter waits until the named test may be run
+func (e errGroupFinder) enter(t *Tester, testName string) func() {
+	if e.gate == nil {
+		return func() {}
+	}
+	return e.gate(t, testName)
+}
+
+func (e errGroupFinder) coveringTests(t *Tester, testBin, outputDir string, allTests []string, includeSubtests bool) ([]Result, error) {
 	var (
 		ctx       = context.Background()
-		coveredBy = []string{}
-		tests     = make([]string, len(allTests))
+		coveredBy = []Result{}
+		tests     = make([][]Result, len(allTests))
 		subs      = make([][]string, len(allTests))
 	)
 	g, ctx := errgroup.WithContext(ctx)
@@ -76,88 +84,205 @@ func (e errGroupFinder) coveringTests(t *Tester, testBin, outputDir string, allT
 		testNum := i
 		testName := allTests[i]
 		g.Go(func() error {
-			return e.runTest(t, testBin, outputDir, testName, testNum, includeSubtests, tests, subs)
+			defer e.enter(t, testName)()
+			var err error
+			tests[testNum], subs[testNum], err = t.coveringRun(testName, testBin, outputDir, includeSubtests)
+			return err
 		})
 	}
 	if err := g.Wait(); err != nil {
-		return []string{}, err
+		return []Result{}, err
 	}
 
-	if includeSubtests {
-		coveringSubs, err := e.coveringSubs(ctx, t, testBin, outputDir, tests, subs)
-		if err != nil {
-			return []string{}, err
+	coveringSubs, err := e.coveringSubs(ctx, t, testBin, outputDir, subs)
+	if err != nil {
+		return []Result{}, err
+	}
+	for i := range tests {
+		coveredBy = append(coveredBy, tests[i]...)
+		coveredBy = append(coveredBy, coveringSubs[i]...)
+	}
+
+	return coveredBy, nil
+}
+
+func (e errGroupFinder) coveringSubs(ctx context.Context, t *Tester, testBin, outputDir string, subs [][]string) ([][]Result, error) {
+	errGroup, _ := errgroup.WithContext(ctx)
+	coveringSubs := make([][]Result, len(subs))
+	for i := range subs {
+		if len(subs[i]) == 0 {
+			continue
 		}
-		for i := range tests {
-			if tests[i] != "" {
-				coveredBy = append(coveredBy, tests[i])
-			}
-			if len(coveringSubs[i]) != 0 {
-				coveredBy = append(coveredBy, coveringSubs[i]...)
+		testNum := i
+		errGroup.Go(func() error {
+			coveringSubTests, err := e.coveringTests(t, testBin, outputDir, subs[testNum], false)
+			if err != nil {
+				return err
 			}
+			coveringSubs[testNum] = coveringSubTests
+			return nil
+		})
+	}
+	if err := errGroup.Wait(); err != nil {
+		return [][]Result{}, err
+	}
+	return coveringSubs, nil
+}
+
+func (e errGroupFinder) profiles(t *Tester, testBin, outputDir string, allTests []string) ([]TestProfile, error) {
+	var (
+		g, _     = errgroup.WithContext(context.Background())
+		profiles = make([]TestProfile, len(allTests))
+	)
+	for i := range allTests {
+		testNum := i
+		g.Go(func() error {
+			defer e.enter(t, allTests[testNum])()
+			var err error
+			profiles[testNum], err = t.profileRun(allTests[testNum], testBin, outputDir)
+			return err
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return []TestProfile{}, err
+	}
+	return profiles, nil
+}
+
+// coveringRun runs a single test and returns its result if it covers the position
+// if includeSubtests is set the subtests which need to be run individually to determine coverage are also returned,
+// unless the test ran only a single subtest in which case the result of that subtest is returned as well
+//
+// the test is run once for each of the configured runs, the result describes the first run which covered the position
+// runs which failed or were skipped only cover the position if configured to include them, see considered
+func (t *Tester) coveringRun(testName, testBin, outputDir string, includeSubtests bool) ([]Result, []string, error) {
+	var (
+		prof        *cover.Profile
+		stdout      *bytes.Buffer
+		block       cover.Block
+		coveredRuns int
+	)
+	for run := 0; run < t.runCount(); run++ {
+		runProf, runStdout, err := t.profile(testName, testBin, outputDir)
+		if err != nil {
+			return nil, nil, err
 		}
-	} else {
-		for i := range tests {
-			if tests[i] != "" {
-				coveredBy = append(coveredBy, tests[i])
-			}
+		runProf = t.withoutSetup(runProf)
+		runBlock, ok := runProf.CoveringBlock(t.testPos.file, t.testPos.line, t.testPos.col)
+		if !ok {
+			continue
 		}
+		status, _, err := testOutcome(bytes.NewReader(runStdout.Bytes()), testName)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error parsing output of '%s': %w", testName, err)
+		}
+		if !t.considered(status) {
+			continue
+		}
+		if coveredRuns == 0 {
+			prof, stdout, block = runProf, runStdout, runBlock
+		}
+		coveredRuns++
+	}
+	if coveredRuns == 0 {
+		return nil, nil, nil
 	}
 
-	return coveredBy, nil
-}
+	stmts := prof.CoveredStatements()
+	result, err := runResult(testName, stdout, block, stmts)
+	if err != nil {
+		return nil, nil, err
+	}
+	setup, _ := t.CoveredBySetup()
+	result.Runs, result.CoveredRuns, result.SetupHits = t.runCount(), coveredRuns, setup.Count
+	results := []Result{result}
+	if !includeSubtests {
+		return results, nil, nil
+	}
 
-func (e errGroupFinder) runTest(t *Tester, testBin, outputDir, testName string, testNum int, includeSubtests bool, tests []string, subs [][]string) error {
-	coverout, stdout, err := t.runCompiledTest(testName, testBin, outputDir)
+	ranSubtests, err := subtests(bytes.NewReader(stdout.Bytes()))
 	if err != nil {
-		return fmt.Errorf("error running test '%s': %s", testName, err)
+		return nil, nil, fmt.Errorf("error finding subtests: %w", err)
+	}
+	subTests := []string{}
+	for _, sub := range ranSubtests {
+		if t.considered(sub.status) {
+			subTests = append(subTests, sub.name)
+		}
+	}
+	results[0].Subtests = subTests
+	// the coverage of the run can only be attributed to a subtest if no other subtest ran
+	if len(subTests) == 1 && len(ranSubtests) == 1 {
+		sub, err := runResult(subTests[0], stdout, block, stmts)
+		if err != nil {
+			return nil, nil, err
+		}
+		sub.Runs, sub.CoveredRuns, sub.SetupHits = t.runCount(), coveredRuns, setup.Count
+		return append(results, sub), nil, nil
 	}
+	return results, subTests, nil
+}
 
-	prof, err := cover.New(coverout)
+// profileRun runs a single test and returns its profile
+func (t *Tester) profileRun(testName, testBin, outputDir string) (TestProfile, error) {
+	prof, stdout, err := t.profile(testName, testBin, outputDir)
+	if err != nil {
+		return TestProfile{}, err
+	}
+	status, elapsed, err := testOutcome(bytes.NewReader(stdout.Bytes()), testName)
 	if err != nil {
-		return fmt.Errorf("error parsing coverage output: %s", err)
+		return TestProfile{}, fmt.Errorf("error parsing output of '%s': %w", testName, err)
 	}
-	if err := coverout.Close(); err != nil {
-		return err
+	testProf := TestProfile{
+		Name:    testName,
+		Kind:    kindOf(testName),
+		Elapsed: elapsed,
+		Status:  status,
+		Profile: prof,
+	}
+	if !t.includeSubtests || strings.Contains(testName, "/") {
+		return testProf, nil
 	}
 
-	if prof.Covers(t.testPos.file, t.testPos.line, t.testPos.col) {
-		tests[testNum] = testName
-		if includeSubtests {
-			subs[testNum], err = subtests(stdout)
-			if err != nil {
-				return fmt.Errorf("error finding subtests: %s", err)
-			}
+	// the output of the top-level test includes every level of its subtests
+	ran, err := subtests(bytes.NewReader(stdout.Bytes()))
+	if err != nil {
+		return TestProfile{}, fmt.Errorf("error parsing output of '%s': %w", testName, err)
+	}
+	for _, sub := range ran {
+		if t.considered(sub.status) {
+			testProf.subtests = append(testProf.subtests, sub.name)
 		}
 	}
-	return nil
+	return testProf, nil
 }
 
-func (e errGroupFinder) coveringSubs(ctx context.Context, t *Tester, testBin, outputDir string, tests []string, subs [][]string) ([][]string, error) {
-	errGroup, _ := errgroup.WithContext(ctx)
-	coveringSubs := make([][]string, len(tests))
-	for i := range tests {
-		if tests[i] == "" {
-			continue
-		}
-		testNum := i
-		errGroup.Go(func() error {
-			if len(subs[testNum]) != 0 {
-				if len(subs[testNum]) == 1 {
-					coveringSubs[testNum] = subs[testNum]
-					return nil
-				}
-				coveringSubTests, err := e.coveringTests(t, testBin, outputDir, subs[testNum], false)
-				if err != nil {
-					return err
-				}
-				coveringSubs[testNum] = coveringSubTests
-			}
-			return nil
-		})
+// profile runs a single test and parses its cover profile
+func (t *Tester) profile(testName, testBin, outputDir string) (*cover.Profile, *bytes.Buffer, error) {
+	coverout, stdout, err := t.runCompiledTest(testName, testBin, outputDir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error running test '%s': %w", testName, err)
 	}
-	if err := errGroup.Wait(); err != nil {
-		return [][]string{}, err
+	defer coverout.Close()
+
+	prof, err := cover.New(coverout)
+	if err != nil {
+		return nil, nil, &ProfileError{Test: testName, Err: err}
 	}
-	return coveringSubs, nil
+	return prof, stdout, nil
+}
+
+// runResult constructs the result of the named test from the output of a run which covered block
+func runResult(testName string, stdout *bytes.Buffer, block cover.Block, stmts int) (Result, error) {
+	status, elapsed, err := testOutcome(bytes.NewReader(stdout.Bytes()), testName)
+	if err != nil {
+		return Result{}, fmt.Errorf("error parsing output of '%s': %w", testName, err)
+	}
+	return Result{
+		Name:       testName,
+		Elapsed:    elapsed,
+		Status:     status,
+		Block:      block,
+		Statements: stmts,
+	}, nil
 }
@@ -0,0 +1,46 @@
+package tester
+
+import (
+	"strings"
+	"time"
+
+	"github.com/ShawnROGrady/go-find-tests/finder"
+)
+
+// loadDirectives parses the directives of the tests of the package, it must be called before any test is run
+// a tester without the directory of its package has no directives
+func (t *Tester) loadDirectives() error {
+	if t.directives != nil || t.dir == "" {
+		return nil
+	}
+	directives, err := finder.PackageDirectives(t.dir)
+	if err != nil {
+		return err
+	}
+	t.directives = directives
+	return nil
+}
+
+// directivesOf returns the directives of the top-level test of the named test
+func (t *Tester) directivesOf(testName string) finder.Directives {
+	return t.directives[strings.SplitN(testName, "/", 2)[0]]
+}
+
+// withoutSkipped removes the tests with a skip directive or which match the skip pattern
+func (t *Tester) withoutSkipped(tests []string) []string {
+	run := make([]string, 0, len(tests))
+	for _, test := range tests {
+		if !t.directivesOf(test).Skip && !t.skipped(test) {
+			run = append(run, test)
+		}
+	}
+	return run
+}
+
+// testTimeout returns the timeout of the named test, a timeout directive overrides the configured timeout
+func (t *Tester) testTimeout(testName string) time.Duration {
+	if timeout := t.directivesOf(testName).Timeout; timeout > 0 {
+		return timeout
+	}
+	return t.timeout
+}
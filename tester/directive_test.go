@@ -0,0 +1,60 @@
+package tester
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+var directiveTests = map[string]struct {
+	run             string
+	timeout         time.Duration
+	expectCoveredBy string
+	expectErr       error
+}{
+	"skip": {
+		run:             "TestFast|TestEndToEnd",
+		expectCoveredBy: "[TestFast]",
+	},
+	"timeout_shortened": {
+		run:       "TestTimedOut",
+		timeout:   time.Hour,
+		expectErr: &TimeoutError{Test: "TestTimedOut", Timeout: 100 * time.Millisecond},
+	},
+	"timeout_extended": {
+		run:             "TestFast|TestExtended",
+		timeout:         200 * time.Millisecond,
+		expectCoveredBy: "[TestFast TestExtended]",
+	},
+}
+
+func TestDirectives(t *testing.T) {
+	for testName, test := range directiveTests {
+		test := test
+		t.Run(testName, func(t *testing.T) {
+			tester, err := New("../testdata/directives/directives.go", 6, 0, Config{Run: test.run, Timeout: test.timeout})
+			if err != nil {
+				t.Fatalf("Unexpected error constructing tester: %s", err)
+			}
+			coveredBy, err := tester.CoveredBy()
+			if test.expectErr != nil {
+				var timeoutErr *TimeoutError
+				if !errors.As(err, &timeoutErr) {
+					t.Fatalf("Unexpected error (expected = %v, actual = %v)", test.expectErr, err)
+				}
+				expected := test.expectErr.(*TimeoutError)
+				if timeoutErr.Test != expected.Test || timeoutErr.Timeout != expected.Timeout {
+					t.Errorf("Unexpected timeout (expected = %s after %s, actual = %s after %s)", expected.Test, expected.Timeout, timeoutErr.Test, timeoutErr.Timeout)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if fmt.Sprint(coveredBy) != test.expectCoveredBy {
+				t.Errorf("Unexpected covering tests (expected = %s, actual = %v)", test.expectCoveredBy, coveredBy)
+			}
+		})
+	}
+}
@@ -2,67 +2,217 @@ package tester
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os/exec"
 	"strings"
+	"time"
 )
 
-// testErr represents an error that occurred while running a test
-type testErr struct {
-	testName string
-	output   string
+// Sentinel errors which can be used with errors.Is to determine the kind of an error returned by the Tester
+var (
+	ErrPackageNotFound = errors.New("package not found")
+	ErrCompile         = errors.New("test compilation failed")
+	ErrTestFailed      = errors.New("test failed")
+	ErrTimeout         = errors.New("test timed out")
+	ErrProfile         = errors.New("unparsable cover profile")
+)
+
+// CommandError is an alternate to exec.ExitError which includes the command that was run and stderr if present
+type CommandError struct {
+	Args   []string // the full command, including the executable
+	Stderr string
+	Err    error
+}
+
+func (c *CommandError) Error() string {
+	if c.Stderr == "" {
+		return c.Err.Error()
+	}
+	return fmt.Sprintf("%s - %s", c.Err, c.Stderr)
+}
+
+// Unwrap returns the underlying error
+func (c *CommandError) Unwrap() error { return c.Err }
+
+// PackageNotFoundError is returned when the go package associated with a path could not be determined
+type PackageNotFoundError struct {
+	Path string
+	Err  error
+}
+
+func (p *PackageNotFoundError) Error() string {
+	return fmt.Sprintf("error finding go pkg from '%s': %s", p.Path, p.Err)
+}
+
+// Unwrap returns the underlying error
+func (p *PackageNotFoundError) Unwrap() error { return p.Err }
+
+// Is reports whether target is ErrPackageNotFound
+func (p *PackageNotFoundError) Is(target error) bool { return target == ErrPackageNotFound }
+
+// CompileError is returned when the test binary for a package could not be built
+type CompileError struct {
+	Package string
+	Err     error
+}
+
+func (c *CompileError) Error() string {
+	return fmt.Sprintf("error compiling test for go pkg %s: %s", c.Package, c.Err)
+}
+
+// Unwrap returns the underlying error
+func (c *CompileError) Unwrap() error { return c.Err }
+
+// Is reports whether target is ErrCompile
+func (c *CompileError) Is(target error) bool { return target == ErrCompile }
+
+// TestError represents an error that occurred while running a test
+type TestError struct {
+	Test   string // name of the failing test
+	Output string // last line of output written by the failing test
+	Err    error
+}
+
+func (t *TestError) Error() string {
+	return fmt.Sprintf("FAIL: %s - %s", t.Test, strings.TrimSpace(t.Output))
 }
 
-func (t *testErr) Error() string {
-	return fmt.Sprintf("FAIL: %s - %s", t.testName, strings.TrimSpace(t.output))
+// Unwrap returns the underlying error
+func (t *TestError) Unwrap() error { return t.Err }
+
+// Is reports whether target is ErrTestFailed
+func (t *TestError) Is(target error) bool { return target == ErrTestFailed }
+
+// TimeoutError is returned when a test exceeds the configured timeout
+type TimeoutError struct {
+	Test    string
+	Timeout time.Duration // zero if the default of the testing package was exceeded
+	Err     error
 }
 
-// parseTestError reads the output of a test run and attempts to construct a human-readable testErr
-func parseTestError(origErr error, output io.Reader) error {
+func (t *TimeoutError) Error() string {
+	if t.Timeout == 0 {
+		// no timeout was set, so the test was stopped by the default of the testing package
+		return fmt.Sprintf("TIMEOUT: %s - exceeded the default timeout", t.Test)
+	}
+	return fmt.Sprintf("TIMEOUT: %s - exceeded %s", t.Test, t.Timeout)
+}
+
+// Unwrap returns the underlying error
+func (t *TimeoutError) Unwrap() error { return t.Err }
+
+// Is reports whether target is ErrTimeout
+func (t *TimeoutError) Is(target error) bool { return target == ErrTimeout }
+
+// ProfileError is returned when the cover profile written by a test could not be read
+type ProfileError struct {
+	Test string
+	Path string
+	Err  error
+}
+
+func (p *ProfileError) Error() string {
+	return fmt.Sprintf("error parsing coverage output of '%s': %s", p.Test, p.Err)
+}
+
+// Unwrap returns the underlying error
+func (p *ProfileError) Unwrap() error { return p.Err }
+
+// Is reports whether target is ErrProfile
+func (p *ProfileError) Is(target error) bool { return target == ErrProfile }
+
+// ExecError is returned by Exec when 'go test' fails while running the tests of a package, after its output was streamed
+type ExecError struct {
+	Package string
+	Err     error
+}
+
+func (e *ExecError) Error() string {
+	return fmt.Sprintf("error running tests of go pkg %s: %s", e.Package, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *ExecError) Unwrap() error { return e.Err }
+
+// ExitCode returns the exit status of 'go test', or -1 if it didn't exit
+func (e *ExecError) ExitCode() int {
+	var exitErr *exec.ExitError
+	if errors.As(e.Err, &exitErr) {
+		return exitErr.ExitCode()
+	}
+	return -1
+}
+
+// parseTestError reads the output of a test run and attempts to construct a human-readable TestError or TimeoutError
+func parseTestError(cmd *exec.Cmd, origErr error, output io.Reader, timeout time.Duration) error {
 	var (
 		scanner      = bufio.NewScanner(output)
 		lastOutEvent TestEvent
+		failed       *TestEvent
+		timedOut     bool
 	)
 
 	if _, ok := origErr.(*exec.ExitError); !ok {
 		// errors running a test will result in an exec.ExitError
 		return origErr
 	}
+	cmdErr := parseCommandErr(cmd, origErr)
 
 	for scanner.Scan() {
 		event := TestEvent{}
 		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
 			// swallow unmarshal error since it won't provide any additional context as to why there was an error initially
-			return origErr
+			return cmdErr
 		}
 
 		switch event.Action {
 		case "output":
-			lastOutEvent = event
+			if strings.HasPrefix(event.Output, "panic: test timed out") {
+				timedOut = true
+			}
+			if failed == nil && !isFraming(event.Output) {
+				lastOutEvent = event
+			}
 		case "fail":
-			return &testErr{testName: event.Test, output: lastOutEvent.Output}
+			if failed == nil {
+				failed = &event
+			}
 		}
 	}
 
-	return parseCommandErr(origErr)
-}
+	if timedOut {
+		var testName string
+		if failed != nil {
+			testName = failed.Test
+		}
+		return &TimeoutError{Test: testName, Timeout: timeout, Err: cmdErr}
+	}
+	if failed != nil {
+		return &TestError{Test: failed.Test, Output: lastOutEvent.Output, Err: cmdErr}
+	}
 
-// commandErr is an alternate to exec.ExitError which includes stderr if present
-type commandErr struct {
-	err    error
-	stderr string
+	return cmdErr
 }
 
-func (c *commandErr) Error() string {
-	return fmt.Sprintf("%s - %s", c.err, c.stderr)
+// isFraming returns whether the output line was written by the testing package to delimit a test, such as '=== RUN'
+func isFraming(output string) bool {
+	output = strings.TrimSpace(output)
+	return strings.HasPrefix(output, "=== ") || strings.HasPrefix(output, "--- ")
 }
 
-func parseCommandErr(origErr error) error {
+// parseCommandErr wraps an error returned by running cmd in a CommandError
+func parseCommandErr(cmd *exec.Cmd, origErr error) error {
+	cmdErr := &CommandError{Args: cmd.Args, Err: origErr}
 	if exitErr, ok := origErr.(*exec.ExitError); ok && len(exitErr.Stderr) != 0 {
-		return &commandErr{err: origErr, stderr: string(exitErr.Stderr)}
+		cmdErr.Stderr = string(exitErr.Stderr)
+	} else if stderr, ok := cmd.Stderr.(*bytes.Buffer); ok {
+		// exec.ExitError only captures stderr if it was not otherwise redirected
+		cmdErr.Stderr = stderr.String()
 	}
 
-	return origErr
+	return cmdErr
 }
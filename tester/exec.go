@@ -0,0 +1,68 @@
+package tester
+
+import "io"
+
+// Exec runs exactly the provided tests with 'go test -v', streaming their output to stdout and stderr
+// the tests of each package are run with a separate command, in the order the packages first appear
+// args are passed to each 'go test' command before the package (e.g. build flags or '-json')
+//
+// if any command fails an *ExecError of the last failing command is returned, which wraps its *exec.ExitError
+func (t *Tester) Exec(results []Result, stdout, stderr io.Writer, args ...string) error {
+	var (
+		pkgs   = []string{}
+		byPkg  = make(map[string][]Result)
+		runErr error
+	)
+	for i := range results {
+		pkg := results[i].Package
+		if pkg == "" {
+			pkg = t.testPos.pkg
+		}
+		if _, ok := byPkg[pkg]; !ok {
+			pkgs = append(pkgs, pkg)
+		}
+		byPkg[pkg] = append(byPkg[pkg], results[i])
+	}
+
+	for _, pkg := range pkgs {
+		cmd := t.ws.command(t.execArgs(pkg, byPkg[pkg], args)...)
+		cmd.Stdout = stdout
+		cmd.Stderr = stderr
+		if err := cmd.Run(); err != nil {
+			runErr = &ExecError{Package: pkg, Err: parseCommandErr(cmd, err)}
+		}
+	}
+	return runErr
+}
+
+// execArgs returns the arguments to 'go' which run exactly the provided tests of the package
+func (t *Tester) execArgs(pkg string, results []Result, args []string) []string {
+	var tests, benchmarks []string
+	for i := range results {
+		if results[i].Kind == KindBenchmark {
+			benchmarks = append(benchmarks, results[i].Name)
+			continue
+		}
+		tests = append(tests, results[i].Name)
+	}
+
+	runExpr := "^$"
+	if len(tests) != 0 {
+		runExpr = RunPattern(tests)
+	}
+	cmdArgs := []string{"test", "-v", "-run", runExpr}
+	if len(benchmarks) != 0 {
+		cmdArgs = append(cmdArgs, "-bench", RunPattern(benchmarks), "-benchtime", "1x")
+	}
+	if t.short {
+		cmdArgs = append(cmdArgs, "-short")
+	}
+	if t.timeout > 0 {
+		cmdArgs = append(cmdArgs, "-timeout", t.timeout.String())
+	}
+	if len(t.execWrapper) != 0 {
+		cmdArgs = append(cmdArgs, "-exec", t.execWrapperFlag())
+	}
+	cmdArgs = append(cmdArgs, args...)
+	return append(cmdArgs, pkg)
+}
@@ -0,0 +1,79 @@
+package tester
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+var execTests = map[string]struct {
+	path           string
+	results        []Result
+	expectExitCode int
+	expectedRuns   []string
+	unexpectedRuns []string
+}{
+	"selected_subtests": {
+		path: "../testdata/subtests/len.go",
+		results: []Result{
+			{Name: "TestIsEmpty", Kind: KindTest},
+			{Name: "TestIsEmpty/empty_input", Kind: KindTest},
+		},
+		expectedRuns:   []string{"=== RUN   TestIsEmpty/empty_input"},
+		unexpectedRuns: []string{"=== RUN   TestIsShort", "=== RUN   TestIsEmpty/short_input"},
+	},
+	"failing_test": {
+		path:           "../testdata/failing/fail.go",
+		results:        []Result{{Name: "TestSum", Kind: KindTest}},
+		expectExitCode: 1,
+		expectedRuns:   []string{"=== RUN   TestSum", "--- FAIL: TestSum"},
+	},
+}
+
+func TestExec(t *testing.T) {
+	for testName, test := range execTests {
+		t.Run(testName, func(t *testing.T) {
+			tester, err := New(test.path, 1, 0, Config{})
+			if err != nil {
+				t.Fatalf("Unexpected error constructing tester: %s", err)
+			}
+
+			var stdout, stderr bytes.Buffer
+			err = tester.Exec(test.results, &stdout, &stderr, "-count=1")
+
+			var execErr *ExecError
+			if test.expectExitCode == 0 && err != nil {
+				t.Fatalf("Unexpected error: %s (stderr = %s)", err, stderr.String())
+			} else if test.expectExitCode != 0 && (!errors.As(err, &execErr) || execErr.ExitCode() != test.expectExitCode) {
+				t.Fatalf("Unexpected error (expected exit code = %d, actual = %v)", test.expectExitCode, err)
+			}
+
+			output := stdout.String()
+			for _, run := range test.expectedRuns {
+				if !strings.Contains(output, run) {
+					t.Errorf("Expected output to contain '%s': %s", run, output)
+				}
+			}
+			for _, run := range test.unexpectedRuns {
+				if strings.Contains(output, run) {
+					t.Errorf("Unexpected '%s' in output: %s", run, output)
+				}
+			}
+		})
+	}
+}
+
+func TestExecArgs(t *testing.T) {
+	tester := &Tester{}
+	results := []Result{
+		{Name: "TestA", Kind: KindTest},
+		{Name: "BenchmarkX", Kind: KindBenchmark},
+		{Name: "BenchmarkY/sub", Kind: KindBenchmark},
+	}
+
+	expected := "test -v -run ^TestA$ -bench ^BenchmarkX$|^BenchmarkY$/^sub$ -benchtime 1x -count=1 ./pkg"
+	if actual := strings.Join(tester.execArgs("./pkg", results, []string{"-count=1"}), " "); actual != expected {
+		t.Errorf("Unexpected args (expected = '%s', actual = '%s')", expected, actual)
+	}
+}
@@ -0,0 +1,152 @@
+package tester
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// splitPattern splits a '-run' or '-skip' pattern as the testing package does: into alternatives separated by '|',
+// each split into the pattern of each level of test names separated by '/', where neither is within brackets or parentheses
+func splitPattern(s string) [][]string {
+	alternatives := [][]string{}
+	for _, alt := range splitUnbracketed(s, '|') {
+		alternatives = append(alternatives, splitUnbracketed(alt, '/'))
+	}
+	return alternatives
+}
+
+// splitUnbracketed splits the pattern at each separator which isn't escaped or within brackets or parentheses
+func splitUnbracketed(s string, sep byte) []string {
+	var (
+		parts = make([]string, 0, strings.Count(s, string(sep))+1)
+		cs    = 0 // depth of character class
+		cp    = 0 // depth of parentheses
+	)
+	for i := 0; i < len(s); {
+		switch s[i] {
+		case '[':
+			cs++
+		case ']':
+			if cs--; cs < 0 { // an unmatched ']' is legal
+				cs = 0
+			}
+		case '(':
+			if cs == 0 {
+				cp++
+			}
+		case ')':
+			if cs == 0 {
+				cp--
+			}
+		case '\\':
+			i++
+		case sep:
+			if cs == 0 && cp == 0 {
+				parts = append(parts, s[:i])
+				s = s[i+1:]
+				i = 0
+				continue
+			}
+		}
+		i++
+	}
+	return append(parts, s)
+}
+
+// compilePattern compiles each level of each alternative of a '-run' or '-skip' pattern
+func compilePattern(s string) ([][]*regexp.Regexp, error) {
+	alternatives := splitPattern(s)
+	compiled := make([][]*regexp.Regexp, len(alternatives))
+	for i, levels := range alternatives {
+		compiled[i] = make([]*regexp.Regexp, len(levels))
+		for j := range levels {
+			var err error
+			if compiled[i][j], err = regexp.Compile(levels[j]); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return compiled, nil
+}
+
+// matchLevels returns whether each level of the name matches the corresponding level of the pattern,
+// levels beyond the end of either are ignored
+func matchLevels(levels []*regexp.Regexp, names []string) bool {
+	for i := 0; i < len(levels) && i < len(names); i++ {
+		if !levels[i].MatchString(names[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// checkFilter returns an error if either pattern is invalid
+func checkFilter(run, skip string) error {
+	if _, err := compilePattern(run); err != nil {
+		return fmt.Errorf("invalid run pattern: %w", err)
+	}
+	if skip == "" {
+		return nil
+	}
+	if _, err := compilePattern(skip); err != nil {
+		return fmt.Errorf("invalid skip pattern: %w", err)
+	}
+	return nil
+}
+
+// listPattern returns the pattern of top-level tests passed to 'go test -list', matching the first level of any alternative
+func (t *Tester) listPattern() string {
+	alternatives := splitPattern(t.run)
+	first := make([]string, len(alternatives))
+	for i := range alternatives {
+		first[i] = alternatives[i][0]
+	}
+	return strings.Join(first, "|")
+}
+
+// runPattern returns the '-test.run' pattern which runs the named test, along with only the subtests selected by
+// the levels below it of the alternatives of the run pattern which match it, or all of them if any alternative has no such levels
+func (t *Tester) runPattern(testName string) string {
+	var (
+		pattern = RunPattern([]string{testName})
+		names   = strings.Split(testName, "/")
+		deeper  = []string{}
+	)
+	compiled, err := compilePattern(t.run)
+	if err != nil {
+		return pattern
+	}
+	for i, levels := range splitPattern(t.run) {
+		if !matchLevels(compiled[i], names) {
+			continue
+		}
+		if len(levels) <= len(names) {
+			return pattern
+		}
+		deeper = append(deeper, pattern+"/"+strings.Join(levels[len(names):], "/"))
+	}
+	if len(deeper) == 0 {
+		return pattern
+	}
+	return strings.Join(deeper, "|")
+}
+
+// skipped returns whether any alternative of the skip pattern matches every level of the test name, so the test is never run
+// as with the testing package, an alternative with more levels than the name doesn't match it
+func (t *Tester) skipped(testName string) bool {
+	if t.skip == "" {
+		return false
+	}
+	compiled, err := compilePattern(t.skip)
+	if err != nil {
+		return false
+	}
+	names := strings.Split(testName, "/")
+	for _, levels := range compiled {
+		if len(levels) <= len(names) && matchLevels(levels, names) {
+			return true
+		}
+	}
+	return false
+}
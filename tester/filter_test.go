@@ -0,0 +1,115 @@
+package tester
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+)
+
+var splitPatternTests = map[string][][]string{
+	"TestParse":             {{"TestParse"}},
+	"TestParse/valid_":      {{"TestParse", "valid_"}},
+	"TestA|TestB/x/y":       {{"TestA"}, {"TestB", "x", "y"}},
+	"TestA/x|y":             {{"TestA", "x"}, {"y"}},
+	"Test(A|B)/x":           {{"Test(A|B)", "x"}},
+	"TestParse/[|/]":        {{"TestParse", "[|/]"}},
+	"Test(A/B)/c":           {{"Test(A/B)", "c"}},
+	`TestParse/a\/b\|c`:     {{"TestParse", `a\/b\|c`}},
+	"TestParse/":            {{"TestParse", ""}},
+	"TestParse/valid_]/all": {{"TestParse", "valid_]", "all"}},
+}
+
+func TestSplitPattern(t *testing.T) {
+	for pattern, expected := range splitPatternTests {
+		t.Run(pattern, func(t *testing.T) {
+			if actual := splitPattern(pattern); fmt.Sprint(actual) != fmt.Sprint(expected) {
+				t.Errorf("Unexpected levels (expected = %q, actual = %q)", expected, actual)
+			}
+		})
+	}
+}
+
+var filterTests = map[string]struct {
+	run, skip       string
+	includeSubtests bool
+	expectErr       bool
+	expectCoveredBy []string
+}{
+	"all": {
+		includeSubtests: true,
+		expectCoveredBy: []string{"TestParse", "TestParse/invalid_empty", "TestParse/valid_negative", "TestParse/valid_positive", "TestParseAll"},
+	},
+	"subtest_level": {
+		run:             "TestParse$/^valid_",
+		includeSubtests: true,
+		expectCoveredBy: []string{"TestParse", "TestParse/valid_negative", "TestParse/valid_positive"},
+	},
+	"subtest_level_only_restricts_subtests": {
+		run:             "TestParse/positive",
+		includeSubtests: true,
+		expectCoveredBy: []string{"TestParse", "TestParse/valid_positive", "TestParseAll"},
+	},
+	"alternative_without_subtest_level": {
+		// as with 'go test', every subtest of TestParse is run since its alternative has no subtest level
+		run:             "TestParse$|TestParseAll/none",
+		includeSubtests: true,
+		expectCoveredBy: []string{"TestParse", "TestParse/invalid_empty", "TestParse/valid_negative", "TestParse/valid_positive", "TestParseAll"},
+	},
+	"alternatives_with_subtest_levels": {
+		run:             "TestParse$/^valid_p|TestParse$/^invalid_|TestParseAll",
+		includeSubtests: true,
+		expectCoveredBy: []string{"TestParse", "TestParse/invalid_empty", "TestParse/valid_positive", "TestParseAll"},
+	},
+	"skip_alternative": {
+		skip:            "TestParseAll|TestParse$/^valid_",
+		includeSubtests: true,
+		expectCoveredBy: []string{"TestParse", "TestParse/invalid_empty"},
+	},
+	"skip_top_level": {
+		skip:            "TestParseAll",
+		expectCoveredBy: []string{"TestParse"},
+	},
+	"skip_subtests": {
+		skip:            "TestParse$/invalid_",
+		includeSubtests: true,
+		expectCoveredBy: []string{"TestParse", "TestParse/valid_negative", "TestParse/valid_positive", "TestParseAll"},
+	},
+	"invalid_run_level": {
+		run:       "TestParse/valid_(",
+		expectErr: true,
+	},
+	"invalid_skip": {
+		skip:      "[",
+		expectErr: true,
+	},
+}
+
+func TestFilter(t *testing.T) {
+	for testName, test := range filterTests {
+		test := test
+		t.Run(testName, func(t *testing.T) {
+			tester, err := New("../testdata/filter/parse.go", 6, 0, Config{
+				Run:             test.run,
+				Skip:            test.skip,
+				IncludeSubtests: test.includeSubtests,
+			})
+			if test.expectErr {
+				if err == nil {
+					t.Error("Unexpectedly no error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error constructing tester: %s", err)
+			}
+			coveredBy, err := tester.CoveredBy()
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			sort.Strings(coveredBy)
+			if fmt.Sprint(coveredBy) != fmt.Sprint(test.expectCoveredBy) {
+				t.Errorf("Unexpected CoveredBy (expected = %v, actual = %v)", test.expectCoveredBy, coveredBy)
+			}
+		})
+	}
+}
@@ -3,14 +3,15 @@ package tester
 import (
 	"bufio"
 	"bytes"
-	"os/exec"
 	"strings"
 )
 
-func findTests(pkg, runExpr string) ([]string, error) {
-	output, err := exec.Command("go", "test", "-list", runExpr, pkg).Output()
+// findTests lists the tests, examples, and fuzz targets (and optionally benchmarks) of the package matching runExpr
+func findTests(ws workspace, pkg, runExpr string, benchmarks bool) ([]string, error) {
+	cmd := ws.command("test", "-list", runExpr, pkg)
+	output, err := cmd.Output()
 	if err != nil {
-		return []string{}, parseCommandErr(err)
+		return []string{}, parseCommandErr(cmd, err)
 	}
 
 	var (
@@ -21,8 +22,17 @@ func findTests(pkg, runExpr string) ([]string, error) {
 
 	for scanner.Scan() {
 		txt := scanner.Text()
-		if strings.HasPrefix(txt, "Test") {
+		switch kindOf(txt) {
+		case KindBenchmark:
+			if benchmarks {
+				tests = append(tests, txt)
+			}
+		case KindExample, KindFuzz:
 			tests = append(tests, txt)
+		default:
+			if strings.HasPrefix(txt, "Test") {
+				tests = append(tests, txt)
+			}
 		}
 	}
 
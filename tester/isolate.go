@@ -0,0 +1,154 @@
+package tester
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// isolatedDirs are the variables which point to per-user state, each is set to its own empty directory when isolating tests
+var isolatedDirs = []string{"HOME", "TMPDIR", "XDG_CONFIG_HOME", "XDG_CACHE_HOME", "XDG_DATA_HOME", "XDG_STATE_HOME", "XDG_RUNTIME_DIR"}
+
+// preservedGoEnv are resolved before isolating tests so that the go command, and tests which run it,
+// keep using the existing build and module caches and go env file
+var preservedGoEnv = []string{"GOCACHE", "GOMODCACHE", "GOPATH", "GOENV"}
+
+// isolated returns whether each test process is run with its own environment or directory
+func (t *Tester) isolated() bool {
+	return t.isolate || t.copyPackage || len(t.env) != 0
+}
+
+// prepareEnv resolves the environment shared by every isolated test process
+// it must be called before any test is run
+func (t *Tester) prepareEnv() error {
+	if !t.isolate || t.goEnv != nil {
+		return nil
+	}
+	goEnv, err := t.ws.goEnv(preservedGoEnv...)
+	if err != nil {
+		return err
+	}
+	t.goEnv = goEnv
+	return nil
+}
+
+// isolateCmd sets up the environment and working directory of a single test process within outputDir
+// the returned function removes every directory created for the process
+func (t *Tester) isolateCmd(cmd *exec.Cmd, outputDir string) (func(), error) {
+	noop := func() {}
+	if !t.isolated() {
+		return noop, nil
+	}
+
+	env := cmd.Env
+	if env == nil {
+		env = os.Environ()
+	}
+	if !t.isolate && !t.copyPackage {
+		cmd.Env = append(env, t.env...)
+		return noop, nil
+	}
+
+	root, err := ioutil.TempDir(outputDir, "env")
+	if err != nil {
+		return noop, err
+	}
+	cleanup := func() { os.RemoveAll(root) }
+
+	if t.isolate {
+		for _, name := range isolatedDirs {
+			dir := filepath.Join(root, strings.ToLower(name))
+			// XDG_RUNTIME_DIR must only be accessible by the user
+			if err := os.Mkdir(dir, 0700); err != nil {
+				cleanup()
+				return noop, err
+			}
+			env = append(env, name+"="+dir)
+		}
+		env = append(env, t.goEnv...)
+	}
+	if t.copyPackage {
+		dir := filepath.Join(root, "pkg")
+		if err := copyPackage(t.dir, dir); err != nil {
+			cleanup()
+			return noop, err
+		}
+		cmd.Dir = dir
+		env = append(env, "PWD="+dir)
+	}
+	cmd.Env = append(env, t.env...)
+	return cleanup, nil
+}
+
+// copyPackage copies the package directory src to dst, along with every subdirectory other than those of nested packages
+// the testdata directory is always copied in full so that tests can access it by relative paths
+func copyPackage(src, dst string) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		switch {
+		case info.IsDir():
+			inTestdata := rel == "testdata" || strings.HasPrefix(rel, "testdata"+string(filepath.Separator))
+			if rel != "." && !inTestdata && skipCopy(path, info.Name()) {
+				return filepath.SkipDir
+			}
+			return os.MkdirAll(target, info.Mode().Perm()|0700)
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case info.Mode().IsRegular():
+			return copyFile(path, target, info.Mode().Perm())
+		}
+		// sockets, devices, etc.
+		return nil
+	})
+}
+
+// skipCopy returns whether the subdirectory of a package is excluded when copying it
+// hidden directories, vendor, and nested packages or modules are not part of the package
+func skipCopy(dir, name string) bool {
+	if strings.HasPrefix(name, ".") || name == "vendor" {
+		return true
+	}
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return false
+	}
+	for _, info := range infos {
+		if !info.IsDir() && (strings.HasSuffix(info.Name(), ".go") || info.Name() == "go.mod") {
+			return true
+		}
+	}
+	return false
+}
+
+func copyFile(src, dst string, perm os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_EXCL|os.O_WRONLY, perm)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
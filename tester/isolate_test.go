@@ -0,0 +1,95 @@
+package tester
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestIsolate(t *testing.T) {
+	for finderName, seq := range map[string]bool{"sequential": true, "errgroup": false} {
+		t.Run(fmt.Sprintf("finder=%s", finderName), func(t *testing.T) {
+			// every test claims its directories on each run, so the second run fails unless the directories are new
+			tester, err := New("../testdata/isolated/isolated.go", 5, 0, Config{
+				Seq:         seq,
+				Runs:        2,
+				Isolate:     true,
+				CopyPackage: true,
+				Env:         []string{"ISOLATED_EXTRA=value"},
+			})
+			if err != nil {
+				t.Fatalf("Unexpected error constructing tester: %s", err)
+			}
+			coveredBy, err := tester.CoveredBy()
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			expected := "[TestHome TestTempDir TestWorkingDir TestEnv]"
+			if fmt.Sprint(coveredBy) != expected {
+				t.Errorf("Unexpected covered by (expected = %s, actual = %v)", expected, coveredBy)
+			}
+		})
+	}
+}
+
+func TestCopyPackage(t *testing.T) {
+	src, err := ioutil.TempDir("", "copy_src")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	defer os.RemoveAll(src)
+	dst, err := ioutil.TempDir("", "copy_dst")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	defer os.RemoveAll(dst)
+
+	files := []string{
+		"pkg.go",
+		"pkg_test.go",
+		"fixtures/data.json",
+		"testdata/input.txt",
+		"testdata/src/main.go",
+		"sub/sub.go",
+		"nested/go.mod",
+		".git/HEAD",
+		"vendor/example.com/dep/dep.go",
+	}
+	for _, file := range files {
+		path := filepath.Join(src, file)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+		if err := ioutil.WriteFile(path, []byte(file), 0644); err != nil {
+			t.Fatalf("Unexpected error: %s", err)
+		}
+	}
+
+	if err := copyPackage(src, filepath.Join(dst, "pkg")); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	expectCopied := map[string]bool{
+		"pkg.go":                        true,
+		"pkg_test.go":                   true,
+		"fixtures/data.json":            true,
+		"testdata/input.txt":            true,
+		"testdata/src/main.go":          true,
+		"sub/sub.go":                    false,
+		"nested/go.mod":                 false,
+		".git/HEAD":                     false,
+		"vendor/example.com/dep/dep.go": false,
+	}
+	for file, expected := range expectCopied {
+		b, err := ioutil.ReadFile(filepath.Join(dst, "pkg", file))
+		if copied := err == nil; copied != expected {
+			t.Errorf("Unexpected copy of %s (expected = %t, actual = %t)", file, expected, copied)
+			continue
+		}
+		if expected && string(b) != file {
+			t.Errorf("Unexpected contents of %s: %s", file, b)
+		}
+	}
+}
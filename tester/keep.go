@@ -0,0 +1,165 @@
+package tester
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// ManifestFile is the name of the manifest written to the directory of Config.KeepDir
+const ManifestFile = "manifest.json"
+
+// Manifest describes the artifacts kept in the directory of Config.KeepDir, all paths are relative to that directory
+type Manifest struct {
+	Packages []KeptPackage `json:"packages"`
+}
+
+// KeptPackage describes the artifacts of the tests of a single package
+type KeptPackage struct {
+	Package string     `json:"package"`
+	Dir     string     `json:"dir"`    // directory of the artifacts
+	Binary  string     `json:"binary"` // compiled test binary
+	Tests   []KeptTest `json:"tests"`  // ordered by test and then run
+}
+
+// KeptTest describes the artifacts of a single run of a test
+type KeptTest struct {
+	Test    string `json:"test"`
+	Run     int    `json:"run"`     // starting from 1, tests are run multiple times with Config.Runs
+	Profile string `json:"profile"` // cover profile, e.g. for 'go tool cover -html'
+	Output  string `json:"output"`  // output of 'go tool test2json'
+	Error   string `json:"error,omitempty"`
+}
+
+// keeper records the artifacts kept in a directory, it is shared by the testers of dependent packages
+type keeper struct {
+	dir string
+
+	mu       sync.Mutex
+	manifest Manifest
+	runs     map[string]int // number of runs of each test, keyed by package and test
+}
+
+// newKeeper makes the directory absolute, since the go command and test processes run from other directories
+func newKeeper(dir string) (*keeper, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &keeper{dir: abs, runs: make(map[string]int)}, nil
+}
+
+// outputDir creates the directory the test binary and profiles are written to, the returned function removes it
+// when keeping artifacts the directory is within the keep directory and is never removed
+func (t *Tester) outputDir() (string, func(), error) {
+	if t.keep == nil {
+		dir, err := ioutil.TempDir("", "test_finder")
+		if err != nil {
+			return "", nil, err
+		}
+		return dir, func() { os.RemoveAll(dir) }, nil
+	}
+	dir, err := t.keep.packageDir(t.testPos.pkg)
+	if err != nil {
+		return "", nil, err
+	}
+	return dir, func() {}, nil
+}
+
+// packageDir returns the directory of the artifacts of the package, removing any left by an earlier invocation
+func (k *keeper) packageDir(pkg string) (string, error) {
+	name := escapeFileName(pkg)
+	dir := filepath.Join(k.dir, name)
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.pkg(pkg) != nil {
+		return dir, nil
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	k.manifest.Packages = append(k.manifest.Packages, KeptPackage{Package: pkg, Dir: name, Tests: []KeptTest{}})
+	return dir, k.write()
+}
+
+// pkg returns the kept package, it must be called with the lock held
+func (k *keeper) pkg(pkg string) *KeptPackage {
+	for i := range k.manifest.Packages {
+		if k.manifest.Packages[i].Package == pkg {
+			return &k.manifest.Packages[i]
+		}
+	}
+	return nil
+}
+
+// keepBinary records the compiled test binary of the package
+func (k *keeper) keepBinary(pkg, binary string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	kept := k.pkg(pkg)
+	if kept == nil {
+		return fmt.Errorf("no kept artifacts of %s", pkg)
+	}
+	kept.Binary = filepath.ToSlash(filepath.Join(kept.Dir, filepath.Base(binary)))
+	return k.write()
+}
+
+// nextRun returns the number of the next run of the test and the name of its artifacts without an extension
+// the first run uses the escaped name of the test, so it is easy to find, later runs are suffixed by their number
+func (k *keeper) nextRun(pkg, testName string) (int, string) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	key := pkg + "\x00" + testName
+	k.runs[key]++
+	run := k.runs[key]
+	if run == 1 {
+		return run, escapeFileName(testName)
+	}
+	return run, fmt.Sprintf("%s.run%d", escapeFileName(testName), run)
+}
+
+// keepRun records the artifacts of a run of a test, writing its output next to its profile
+func (k *keeper) keepRun(pkg string, test KeptTest, output []byte, outputDir string) error {
+	if err := ioutil.WriteFile(filepath.Join(outputDir, test.Output), output, 0644); err != nil {
+		return err
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	kept := k.pkg(pkg)
+	if kept == nil {
+		return fmt.Errorf("no kept artifacts of %s", pkg)
+	}
+	test.Profile = filepath.ToSlash(filepath.Join(kept.Dir, test.Profile))
+	test.Output = filepath.ToSlash(filepath.Join(kept.Dir, test.Output))
+	kept.Tests = append(kept.Tests, test)
+	sort.Slice(kept.Tests, func(i, j int) bool {
+		if kept.Tests[i].Test != kept.Tests[j].Test {
+			return kept.Tests[i].Test < kept.Tests[j].Test
+		}
+		return kept.Tests[i].Run < kept.Tests[j].Run
+	})
+	return k.write()
+}
+
+// write replaces the manifest, it must be called with the lock held
+// the manifest is rewritten as each artifact is kept so it is complete even if testing is interrupted
+func (k *keeper) write() error {
+	b, err := json.MarshalIndent(k.manifest, "", "\t")
+	if err != nil {
+		return err
+	}
+	tmp := filepath.Join(k.dir, ManifestFile+".tmp")
+	if err := ioutil.WriteFile(tmp, append(b, '\n'), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(k.dir, ManifestFile))
+}
@@ -0,0 +1,102 @@
+package tester
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ShawnROGrady/go-find-tests/cover"
+)
+
+func TestKeepDir(t *testing.T) {
+	file, err := filepath.Abs("../testdata/flaky/parity.go")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	// a relative directory is relative to the working directory, rather than the module root or package directory
+	for testName, relative := range map[string]bool{"absolute": false, "relative": true} {
+		t.Run(testName, func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "keep")
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			defer os.RemoveAll(dir)
+
+			keepDir := filepath.Join(dir, "kept")
+			if relative {
+				defer chdir(t, dir)()
+				keepDir = "kept"
+			}
+			testKeepDir(t, file, keepDir)
+		})
+	}
+}
+
+// chdir changes the working directory until the returned function is called
+func chdir(t *testing.T, dir string) func() {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatalf("Unexpected error changing directory: %s", err)
+	}
+	return func() {
+		if err := os.Chdir(wd); err != nil {
+			t.Fatalf("Unexpected error restoring directory: %s", err)
+		}
+	}
+}
+
+func testKeepDir(t *testing.T, file, keepDir string) {
+	defer setFlakyCounter(t)()
+	tester, err := New(file, 5, 0, Config{Runs: 2, KeepDir: keepDir})
+	if err != nil {
+		t.Fatalf("Unexpected error constructing tester: %s", err)
+	}
+	if _, err := tester.Results(); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	b, err := ioutil.ReadFile(filepath.Join(keepDir, ManifestFile))
+	if err != nil {
+		t.Fatalf("Unexpected error reading manifest: %s", err)
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(b, &manifest); err != nil {
+		t.Fatalf("Unexpected error parsing manifest: %s", err)
+	}
+	if len(manifest.Packages) != 1 {
+		t.Fatalf("Unexpected number of packages (expected = 1, actual = %d)", len(manifest.Packages))
+	}
+
+	pkg := manifest.Packages[0]
+	if _, err := os.Stat(filepath.Join(keepDir, pkg.Binary)); err != nil {
+		t.Errorf("Test binary not kept: %s", err)
+	}
+	actual := []string{}
+	for _, kept := range pkg.Tests {
+		actual = append(actual, fmt.Sprintf("%s:%d:%s", kept.Test, kept.Run, filepath.Base(kept.Profile)))
+
+		f, err := os.Open(filepath.Join(keepDir, kept.Profile))
+		if err != nil {
+			t.Errorf("Profile of %s not kept: %s", kept.Test, err)
+			continue
+		}
+		if _, err := cover.New(f); err != nil {
+			t.Errorf("Unexpected error parsing kept profile of %s: %s", kept.Test, err)
+		}
+		f.Close()
+		if output, err := ioutil.ReadFile(filepath.Join(keepDir, kept.Output)); err != nil || len(output) == 0 {
+			t.Errorf("Output of %s not kept (output = %q, err = %v)", kept.Test, output, err)
+		}
+	}
+	expected := "[TestAlternating:1:TestAlternating.out TestAlternating:2:TestAlternating.run2.out TestEven:1:TestEven.out TestEven:2:TestEven.run2.out]"
+	if fmt.Sprint(actual) != expected {
+		t.Errorf("Unexpected kept tests (expected = %s, actual = %v)", expected, actual)
+	}
+}
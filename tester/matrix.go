@@ -0,0 +1,62 @@
+package tester
+
+import (
+	"sort"
+
+	"github.com/ShawnROGrady/go-find-tests/cover"
+)
+
+// Matrix is the coverage of every block of a package by every test
+type Matrix struct {
+	Package string
+	Tests   []string      // sorted by name
+	Blocks  []cover.Block // ordered by file and position, Count is the total over all tests
+	// Counts holds the number of times each test executed each block, indexed by test and then block
+	Counts [][]int
+}
+
+// NewMatrix constructs the coverage matrix of the profiles
+// blocks which are not in the profile of a test are treated as not executed by it
+func NewMatrix(profiles []TestProfile) Matrix {
+	sorted := make([]TestProfile, len(profiles))
+	copy(sorted, profiles)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	var (
+		m = Matrix{
+			Tests:  make([]string, len(sorted)),
+			Blocks: []cover.Block{},
+			Counts: make([][]int, len(sorted)),
+		}
+		blockIndex = make(map[cover.BlockKey]int)
+		testBlocks = make([][]cover.Block, len(sorted))
+	)
+	for i := range sorted {
+		if m.Package == "" {
+			m.Package = sorted[i].Package
+		}
+		m.Tests[i] = sorted[i].Name
+		testBlocks[i] = sorted[i].Profile.Blocks()
+		for _, b := range testBlocks[i] {
+			if _, ok := blockIndex[b.Key()]; !ok {
+				blockIndex[b.Key()] = -1
+				b.Count = 0
+				m.Blocks = append(m.Blocks, b)
+			}
+		}
+	}
+	cover.SortBlocks(m.Blocks)
+	for i := range m.Blocks {
+		blockIndex[m.Blocks[i].Key()] = i
+	}
+
+	for i := range testBlocks {
+		m.Counts[i] = make([]int, len(m.Blocks))
+		for _, b := range testBlocks[i] {
+			j := blockIndex[b.Key()]
+			m.Counts[i][j] += b.Count
+			m.Blocks[j].Count += b.Count
+		}
+	}
+	return m
+}
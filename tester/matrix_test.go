@@ -0,0 +1,46 @@
+package tester
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ShawnROGrady/go-find-tests/cover"
+)
+
+func TestNewMatrix(t *testing.T) {
+	profile := func(name, coverOut string) TestProfile {
+		prof, err := cover.New(strings.NewReader(coverOut))
+		if err != nil {
+			t.Fatalf("Unexpected error parsing profile: %s", err)
+		}
+		return TestProfile{Package: "example.com/pkg", Name: name, Profile: prof}
+	}
+
+	m := NewMatrix([]TestProfile{
+		profile("TestB", "mode: count\npkg/b.go:1.1,2.1 1 2\npkg/a.go:7.1,8.1 3 0\npkg/a.go:3.1,4.1 2 1"),
+		// b.go is missing from the profile of TestA
+		profile("TestA", "mode: count\npkg/a.go:3.1,4.1 2 4\npkg/a.go:7.1,8.1 3 1"),
+	})
+
+	if m.Package != "example.com/pkg" {
+		t.Errorf("Unexpected package: %s", m.Package)
+	}
+	if fmt.Sprint(m.Tests) != "[TestA TestB]" {
+		t.Errorf("Unexpected tests (expected = [TestA TestB], actual = %v)", m.Tests)
+	}
+
+	expectedBlocks := []cover.Block{
+		{File: "a.go", StartLine: 3, StartCol: 1, EndLine: 4, EndCol: 1, NumStmt: 2, Count: 5},
+		{File: "a.go", StartLine: 7, StartCol: 1, EndLine: 8, EndCol: 1, NumStmt: 3, Count: 1},
+		{File: "b.go", StartLine: 1, StartCol: 1, EndLine: 2, EndCol: 1, NumStmt: 1, Count: 2},
+	}
+	if fmt.Sprint(m.Blocks) != fmt.Sprint(expectedBlocks) {
+		t.Errorf("Unexpected blocks (expected = %v, actual = %v)", expectedBlocks, m.Blocks)
+	}
+
+	expectedCounts := [][]int{{4, 1, 0}, {1, 0, 2}}
+	if fmt.Sprint(m.Counts) != fmt.Sprint(expectedCounts) {
+		t.Errorf("Unexpected counts (expected = %v, actual = %v)", expectedCounts, m.Counts)
+	}
+}
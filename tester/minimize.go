@@ -0,0 +1,101 @@
+package tester
+
+import (
+	"time"
+
+	"github.com/ShawnROGrady/go-find-tests/cover"
+)
+
+// minCost is the cost assigned to tests which report no elapsed time
+// test2json only reports elapsed time to the hundredth of a second so fast tests are frequently free
+const minCost = time.Millisecond
+
+// LineRange restricts minimization to the statements of a file within an inclusive range of lines
+type LineRange struct {
+	File       string // base name of the file
+	Start, End int
+}
+
+// Minimized is a subset of a suite which retains the coverage of the full suite
+type Minimized struct {
+	Tests        []TestProfile // the chosen tests, in the order they were chosen
+	Statements   int           // statements covered by the full suite
+	Retained     int           // statements covered by the chosen tests
+	Elapsed      time.Duration // total elapsed time of the chosen tests
+	SuiteElapsed time.Duration // total elapsed time of the full suite
+}
+
+// Saved returns the time saved by running only the chosen tests
+func (m Minimized) Saved() time.Duration {
+	return m.SuiteElapsed - m.Elapsed
+}
+
+// Minimize chooses a small set of tests which together cover every statement covered by the full suite
+// if lines is non-nil only the statements within the range are considered
+//
+// tests are chosen greedily by the number of newly covered statements per unit of elapsed time
+func Minimize(profiles []TestProfile, lines *LineRange) Minimized {
+	var (
+		sets      = make([]cover.Set, len(profiles))
+		remaining = make(cover.Set)
+		min       = Minimized{Tests: []TestProfile{}}
+	)
+	for i := range profiles {
+		sets[i] = profiles[i].Profile.Covered()
+		if lines != nil {
+			sets[i] = sets[i].Lines(lines.File, lines.Start, lines.End)
+		}
+		remaining = remaining.Union(sets[i])
+		min.SuiteElapsed += profiles[i].Elapsed
+	}
+	min.Statements = remaining.Statements()
+
+	chosen := make([]bool, len(profiles))
+	for len(remaining) != 0 {
+		best, bestGain := -1, 0
+		for i := range profiles {
+			if chosen[i] {
+				continue
+			}
+			gain := sets[i].Intersect(remaining).Statements()
+			if gain == 0 {
+				continue
+			}
+			if best == -1 || betterCover(gain, cost(profiles[i]), profiles[i].Name, bestGain, cost(profiles[best]), profiles[best].Name) {
+				best, bestGain = i, gain
+			}
+		}
+		if best == -1 {
+			// remaining blocks contain no statements
+			break
+		}
+
+		chosen[best] = true
+		remaining = remaining.Difference(sets[best])
+		min.Tests = append(min.Tests, profiles[best])
+		min.Retained += bestGain
+		min.Elapsed += profiles[best].Elapsed
+	}
+	return min
+}
+
+func cost(p TestProfile) time.Duration {
+	if p.Elapsed < minCost {
+		return minCost
+	}
+	return p.Elapsed
+}
+
+// betterCover reports whether covering gainA statements at costA is preferable to covering gainB statements at costB
+// ties are broken by the larger gain, then by name
+func betterCover(gainA int, costA time.Duration, nameA string, gainB int, costB time.Duration, nameB string) bool {
+	// compare gainA/costA to gainB/costB without division
+	a, b := int64(gainA)*int64(costB), int64(gainB)*int64(costA)
+	if a != b {
+		return a > b
+	}
+	if gainA != gainB {
+		return gainA > gainB
+	}
+	return nameA < nameB
+}
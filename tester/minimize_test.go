@@ -0,0 +1,83 @@
+package tester
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ShawnROGrady/go-find-tests/cover"
+)
+
+// block statements: 3.10 (2), 7.10 (1), 11.10 (4), 20.10 (3)
+const minimizeProfileFmt = `mode: count
+pkg/a.go:3.10,5.2 2 %d
+pkg/a.go:7.10,9.2 1 %d
+pkg/a.go:11.10,15.2 4 %d
+pkg/a.go:20.10,22.2 3 %d`
+
+func minimizeProfile(t *testing.T, name string, elapsed time.Duration, counts ...interface{}) TestProfile {
+	t.Helper()
+	prof, err := cover.New(strings.NewReader(fmt.Sprintf(minimizeProfileFmt, counts...)))
+	if err != nil {
+		t.Fatalf("Unexpected error parsing profile: %s", err)
+	}
+	return TestProfile{Name: name, Elapsed: elapsed, Profile: prof}
+}
+
+func TestMinimize(t *testing.T) {
+	profiles := []TestProfile{
+		minimizeProfile(t, "TestEverything", time.Second, 1, 1, 1, 1),
+		minimizeProfile(t, "TestFirstHalf", 10*time.Millisecond, 1, 1, 1, 0),
+		minimizeProfile(t, "TestSecondHalf", 10*time.Millisecond, 0, 0, 1, 1),
+		minimizeProfile(t, "TestFirst", 10*time.Millisecond, 1, 0, 0, 0),
+	}
+
+	var minimizeTests = map[string]struct {
+		lines              *LineRange
+		expectedTests      []string
+		expectedStatements int
+		expectedElapsed    time.Duration
+	}{
+		"full_suite": {
+			expectedTests:      []string{"TestFirstHalf", "TestSecondHalf"},
+			expectedStatements: 10,
+			expectedElapsed:    20 * time.Millisecond,
+		},
+		"line_range": {
+			lines:              &LineRange{File: "a.go", Start: 1, End: 4},
+			expectedTests:      []string{"TestFirst"},
+			expectedStatements: 2,
+			expectedElapsed:    10 * time.Millisecond,
+		},
+		"other_file": {
+			lines:              &LineRange{File: "b.go", Start: 1, End: 100},
+			expectedTests:      []string{},
+			expectedStatements: 0,
+			expectedElapsed:    0,
+		},
+	}
+
+	for testName, test := range minimizeTests {
+		t.Run(testName, func(t *testing.T) {
+			min := Minimize(profiles, test.lines)
+
+			names := make([]string, len(min.Tests))
+			for i := range min.Tests {
+				names[i] = min.Tests[i].Name
+			}
+			if fmt.Sprint(names) != fmt.Sprint(test.expectedTests) {
+				t.Errorf("Unexpected tests (expected = %v, actual = %v)", test.expectedTests, names)
+			}
+			if min.Statements != test.expectedStatements || min.Retained != test.expectedStatements {
+				t.Errorf("Unexpected statements (expected = %d, actual = %d of %d)", test.expectedStatements, min.Retained, min.Statements)
+			}
+			if min.Elapsed != test.expectedElapsed {
+				t.Errorf("Unexpected elapsed time (expected = %s, actual = %s)", test.expectedElapsed, min.Elapsed)
+			}
+			if saved := min.SuiteElapsed - test.expectedElapsed; min.Saved() != saved {
+				t.Errorf("Unexpected time saved (expected = %s, actual = %s)", saved, min.Saved())
+			}
+		})
+	}
+}
@@ -0,0 +1,182 @@
+package tester
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Module is a go module along with each of its packages
+type Module struct {
+	Path      string // module path
+	Dir       string // absolute directory containing go.mod
+	GoVersion string // version of the go toolchain used to build the tests
+	Packages  []ModulePackage
+}
+
+// ModulePackage is a package of a module
+type ModulePackage struct {
+	ImportPath string
+	Dir        string   // absolute directory of the package
+	GoFiles    []string // names of the non-test go files of the package
+	// Hash changes whenever any input of the test binary of the package changes,
+	// including the source of every dependency within the module and the version of every other dependency
+	Hash     string
+	HasTests bool // whether the package has any test files
+}
+
+// ModuleRoot returns the directory of the go.mod which owns dir, or an empty string if there is none
+func ModuleRoot(dir string) string {
+	return findModRoot(dir)
+}
+
+// moduleListed is the subset of the output of 'go list -deps -test -json' used to hash the inputs of each package
+type moduleListed struct {
+	ImportPath string
+	Dir        string
+	ForTest    string
+	DepOnly    bool
+	Standard   bool
+	Module     *struct {
+		Path    string
+		Version string
+		Main    bool
+		Replace *struct{}
+	}
+	GoFiles, CgoFiles, CFiles, CXXFiles, HFiles, SFiles, SysoFiles, EmbedFiles []string
+	TestGoFiles, XTestGoFiles, TestEmbedFiles, XTestEmbedFiles                 []string
+	Deps                                                                       []string
+}
+
+// ListModule lists every package of the module which owns dir (i.e. './...' from the root of the module)
+// with the provided comma separated build tags
+func ListModule(dir, tags string) (Module, error) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return Module{}, err
+	}
+	ws, err := findWorkspace(abs)
+	if err != nil {
+		return Module{}, err
+	}
+	if ws.modRoot == "" {
+		return Module{}, fmt.Errorf("no go.mod found at or above %s", abs)
+	}
+	ws = ws.withTags(tags)
+
+	cmd := ws.command("env", "GOVERSION")
+	version, err := cmd.Output()
+	if err != nil {
+		return Module{}, parseCommandErr(cmd, err)
+	}
+
+	cmd = ws.command("list", "-e", "-deps", "-test", "-json", "./...")
+	output, err := cmd.Output()
+	if err != nil {
+		return Module{}, parseCommandErr(cmd, err)
+	}
+
+	var (
+		mod    = Module{Dir: ws.modRoot, GoVersion: strings.TrimSpace(string(version)), Packages: []ModulePackage{}}
+		listed = make(map[string]*moduleListed)
+		roots  = []*moduleListed{}
+		dec    = json.NewDecoder(bytes.NewReader(output))
+	)
+	for {
+		pkg := &moduleListed{}
+		if err := dec.Decode(pkg); err == io.EOF {
+			break
+		} else if err != nil {
+			return Module{}, fmt.Errorf("error parsing output of go list: %w", err)
+		}
+		listed[pkg.ImportPath] = pkg
+		// packages which fail to load are still listed so that the error is reported when their tests are compiled
+		if !pkg.DepOnly && pkg.ForTest == "" && !strings.HasSuffix(pkg.ImportPath, ".test") {
+			roots = append(roots, pkg)
+			if mod.Path == "" && pkg.Module != nil && pkg.Module.Main {
+				mod.Path = pkg.Module.Path
+			}
+		}
+	}
+
+	fileHashes := make(map[string]string)
+	for _, pkg := range roots {
+		hash, err := inputHash(mod.GoVersion, pkg, listed, fileHashes)
+		if err != nil {
+			return Module{}, fmt.Errorf("error hashing inputs of %s: %w", pkg.ImportPath, err)
+		}
+		mod.Packages = append(mod.Packages, ModulePackage{
+			ImportPath: pkg.ImportPath,
+			Dir:        pkg.Dir,
+			GoFiles:    pkg.GoFiles,
+			Hash:       hash,
+			HasTests:   len(pkg.TestGoFiles) != 0 || len(pkg.XTestGoFiles) != 0,
+		})
+	}
+	sort.Slice(mod.Packages, func(i, j int) bool { return mod.Packages[i].ImportPath < mod.Packages[j].ImportPath })
+	return mod, nil
+}
+
+// inputHash hashes the package along with every dependency of its test binary
+// standard library packages are identified by the go version and versioned modules by their version,
+// so only the source within the module (or replaced by a local directory) is read
+func inputHash(goVersion string, pkg *moduleListed, listed map[string]*moduleListed, fileHashes map[string]string) (string, error) {
+	deps := pkg.Deps
+	if testMain, ok := listed[pkg.ImportPath+".test"]; ok {
+		deps = testMain.Deps
+	}
+	inputs := append([]string{pkg.ImportPath}, deps...)
+	sort.Strings(inputs)
+
+	h := sha256.New()
+	fmt.Fprintf(h, "go %s\n", goVersion)
+	for _, name := range inputs {
+		dep, ok := listed[name]
+		if !ok || strings.HasSuffix(name, ".test") {
+			continue
+		}
+		switch {
+		case dep.Standard:
+			fmt.Fprintf(h, "std %s\n", name)
+		case dep.Module != nil && !dep.Module.Main && dep.Module.Replace == nil && dep.Module.Version != "":
+			fmt.Fprintf(h, "mod %s@%s\n", name, dep.Module.Version)
+		default:
+			fmt.Fprintf(h, "pkg %s\n", name)
+			files := [][]string{
+				dep.GoFiles, dep.CgoFiles, dep.CFiles, dep.CXXFiles, dep.HFiles, dep.SFiles, dep.SysoFiles, dep.EmbedFiles,
+				dep.TestGoFiles, dep.XTestGoFiles, dep.TestEmbedFiles, dep.XTestEmbedFiles,
+			}
+			for _, names := range files {
+				for _, file := range names {
+					fileHash, err := hashFile(filepath.Join(dep.Dir, file), fileHashes)
+					if err != nil {
+						return "", err
+					}
+					fmt.Fprintf(h, "%s %s\n", file, fileHash)
+				}
+			}
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// hashFile hashes the contents of the file, caching the result since many packages share dependencies
+func hashFile(path string, cache map[string]string) (string, error) {
+	if hash, ok := cache[path]; ok {
+		return hash, nil
+	}
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	cache[path] = hex.EncodeToString(sum[:])
+	return cache[path], nil
+}
@@ -0,0 +1,35 @@
+package tester
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestListModule(t *testing.T) {
+	mod, err := ListModule(filepath.Join("..", "testdata", "nested"), "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if mod.Path != "example.com/nested" {
+		t.Errorf("Unexpected module path: %s", mod.Path)
+	}
+	if len(mod.Packages) != 1 {
+		t.Fatalf("Unexpected packages: %+v", mod.Packages)
+	}
+
+	pkg := mod.Packages[0]
+	if pkg.ImportPath != "example.com/nested" || !pkg.HasTests || pkg.Hash == "" {
+		t.Errorf("Unexpected package: %+v", pkg)
+	}
+	if expected, _ := filepath.Abs(filepath.Join("..", "testdata", "nested")); pkg.Dir != expected {
+		t.Errorf("Unexpected dir (expected = %s, actual = %s)", expected, pkg.Dir)
+	}
+
+	again, err := ListModule(filepath.Join("..", "testdata", "nested"), "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if again.Packages[0].Hash != pkg.Hash {
+		t.Errorf("Hash of unchanged package changed (before = %s, after = %s)", pkg.Hash, again.Packages[0].Hash)
+	}
+}
@@ -0,0 +1,117 @@
+package tester
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// RunPattern returns a pattern for 'go test -run' which matches exactly the provided tests and subtests
+//
+// each level of a test name is anchored and separated by '/', and each test is an alternative separated by '|'
+// tests with a selected subtest are omitted so that only the selected subtests of them are run
+// see: 'go help testflag'
+func RunPattern(tests []string) string {
+	selected := make(map[string]bool, len(tests))
+	for _, test := range tests {
+		selected[test] = true
+	}
+	hasSelectedChild := make(map[string]bool)
+	for _, test := range tests {
+		for sep := strings.LastIndex(test, "/"); sep != -1; sep = strings.LastIndex(test[:sep], "/") {
+			hasSelectedChild[test[:sep]] = true
+		}
+	}
+
+	alternatives := []string{}
+	for test := range selected {
+		if hasSelectedChild[test] {
+			continue
+		}
+		levels := strings.Split(test, "/")
+		for i := range levels {
+			levels[i] = "^" + regexp.QuoteMeta(levels[i]) + "$"
+		}
+		alternatives = append(alternatives, strings.Join(levels, "/"))
+	}
+	sort.Strings(alternatives)
+	return strings.Join(alternatives, "|")
+}
+
+// RunFlags returns the values of '-run', '-skip', and '-bench' which select exactly the provided results
+//
+// '-skip' is used for a test with covering subtests when excluding its other subtests is shorter than listing the covering ones,
+// in which case subtests added to the test later will also be selected
+// benchmarks are only selected by '-bench', the returned skip and bench are empty if they are not needed
+func RunFlags(results []Result) (run, skip, bench string) {
+	var (
+		selected   = make(map[string]bool, len(results))
+		benchmarks = []string{}
+	)
+	for i := range results {
+		if results[i].Kind == KindBenchmark {
+			benchmarks = append(benchmarks, results[i].Name)
+			continue
+		}
+		selected[results[i].Name] = true
+	}
+
+	var (
+		wholeTests = make(map[string]bool)
+		skipped    = []string{}
+	)
+	for i := range results {
+		if len(results[i].Subtests) == 0 {
+			continue
+		}
+		prefix := results[i].Name + "/"
+
+		var covering, excluded []string
+		for name := range selected {
+			if strings.HasPrefix(name, prefix) && !hasSelectedChild(name, selected) {
+				covering = append(covering, name)
+			}
+		}
+		for _, sub := range results[i].Subtests {
+			// only the highest non-covering subtest of each branch needs to be skipped
+			if !selected[sub] && (parentName(sub) == results[i].Name || selected[parentName(sub)]) {
+				excluded = append(excluded, sub)
+			}
+		}
+		if len(covering) != 0 && len(excluded) < len(covering) {
+			wholeTests[results[i].Name] = true
+			skipped = append(skipped, excluded...)
+		}
+	}
+
+	tests := []string{}
+	for name := range selected {
+		if top := strings.SplitN(name, "/", 2)[0]; top != name && wholeTests[top] {
+			continue
+		}
+		tests = append(tests, name)
+	}
+
+	run = RunPattern(tests)
+	if run == "" {
+		// match no tests rather than all of them
+		run = "^$"
+	}
+	return run, RunPattern(skipped), RunPattern(benchmarks)
+}
+
+func parentName(test string) string {
+	if sep := strings.LastIndex(test, "/"); sep != -1 {
+		return test[:sep]
+	}
+	return ""
+}
+
+func hasSelectedChild(test string, selected map[string]bool) bool {
+	for name := range selected {
+		if parentName(name) == test {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,136 @@
+package tester
+
+import (
+	"regexp"
+	"testing"
+)
+
+var runPatternTests = map[string]struct {
+	tests           []string
+	expectedPattern string
+}{
+	"single_test": {
+		tests:           []string{"TestA"},
+		expectedPattern: "^TestA$",
+	},
+	"prefixed_names_anchored": {
+		tests:           []string{"TestA", "TestAB"},
+		expectedPattern: "^TestA$|^TestAB$",
+	},
+	"parent_with_selected_subtest": {
+		tests:           []string{"TestA", "TestA/empty_input", "TestB"},
+		expectedPattern: "^TestA$/^empty_input$|^TestB$",
+	},
+	"nested_subtests": {
+		tests:           []string{"TestA", "TestA/outer", "TestA/outer/inner"},
+		expectedPattern: "^TestA$/^outer$/^inner$",
+	},
+	"metacharacters_escaped": {
+		tests:           []string{"TestA/a|b", "TestA/(1.5)"},
+		expectedPattern: `^TestA$/^\(1\.5\)$|^TestA$/^a\|b$`,
+	},
+	"no_tests": {
+		tests:           []string{},
+		expectedPattern: "",
+	},
+}
+
+func TestRunPattern(t *testing.T) {
+	for testName, test := range runPatternTests {
+		t.Run(testName, func(t *testing.T) {
+			pattern := RunPattern(test.tests)
+			if pattern != test.expectedPattern {
+				t.Errorf("Unexpected pattern (expected = '%s', actual = '%s')", test.expectedPattern, pattern)
+			}
+			if _, err := regexp.Compile(pattern); err != nil {
+				t.Errorf("Pattern is not a valid regular expression: %s", err)
+			}
+		})
+	}
+}
+
+var runFlagsTests = map[string]struct {
+	results       []Result
+	expectedRun   string
+	expectedSkip  string
+	expectedBench string
+}{
+	"top_level_only": {
+		results:     []Result{{Name: "TestA"}, {Name: "TestAB"}},
+		expectedRun: "^TestA$|^TestAB$",
+	},
+	"few_covering_subtests": {
+		results: []Result{
+			{Name: "TestA", Subtests: []string{"TestA/one", "TestA/two", "TestA/three"}},
+			{Name: "TestA/one"},
+		},
+		expectedRun: "^TestA$/^one$",
+	},
+	"most_subtests_covering": {
+		results: []Result{
+			{Name: "TestA", Subtests: []string{"TestA/one", "TestA/two", "TestA/three"}},
+			{Name: "TestA/one"},
+			{Name: "TestA/two"},
+			{Name: "TestB"},
+		},
+		expectedRun:  "^TestA$|^TestB$",
+		expectedSkip: "^TestA$/^three$",
+	},
+	"all_subtests_covering": {
+		results: []Result{
+			{Name: "TestA", Subtests: []string{"TestA/one", "TestA/two"}},
+			{Name: "TestA/one"},
+			{Name: "TestA/two"},
+		},
+		expectedRun: "^TestA$",
+	},
+	"nested_subtests": {
+		results: []Result{
+			{Name: "TestA", Subtests: []string{"TestA/one", "TestA/one/w", "TestA/one/x", "TestA/one/y", "TestA/one/z", "TestA/two", "TestA/two/x"}},
+			{Name: "TestA/one"},
+			{Name: "TestA/one/w"},
+			{Name: "TestA/one/x"},
+			{Name: "TestA/one/y"},
+		},
+		expectedRun:  "^TestA$",
+		expectedSkip: "^TestA$/^one$/^z$|^TestA$/^two$",
+	},
+	"no_results": {
+		results:     []Result{},
+		expectedRun: "^$",
+	},
+	"benchmarks": {
+		results: []Result{
+			{Name: "TestA"},
+			{Name: "BenchmarkF", Kind: KindBenchmark, Subtests: []string{"BenchmarkF/small", "BenchmarkF/large"}},
+			{Name: "BenchmarkF/small", Kind: KindBenchmark},
+			{Name: "BenchmarkG", Kind: KindBenchmark},
+		},
+		expectedRun:   "^TestA$",
+		expectedBench: "^BenchmarkF$/^small$|^BenchmarkG$",
+	},
+	"only_benchmarks": {
+		results: []Result{
+			{Name: "BenchmarkF", Kind: KindBenchmark},
+		},
+		expectedRun:   "^$",
+		expectedBench: "^BenchmarkF$",
+	},
+}
+
+func TestRunFlags(t *testing.T) {
+	for testName, test := range runFlagsTests {
+		t.Run(testName, func(t *testing.T) {
+			run, skip, bench := RunFlags(test.results)
+			if run != test.expectedRun {
+				t.Errorf("Unexpected run pattern (expected = '%s', actual = '%s')", test.expectedRun, run)
+			}
+			if skip != test.expectedSkip {
+				t.Errorf("Unexpected skip pattern (expected = '%s', actual = '%s')", test.expectedSkip, skip)
+			}
+			if bench != test.expectedBench {
+				t.Errorf("Unexpected bench pattern (expected = '%s', actual = '%s')", test.expectedBench, bench)
+			}
+		})
+	}
+}
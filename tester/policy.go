@@ -0,0 +1,124 @@
+package tester
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+/*
+policyFinder runs tests in parallel like errGroupFinder,
+except that serial tests never run concurrently with any other test
+and tests of the same group never run concurrently with each other
+*/
+type policyFinder struct{}
+
+func (p policyFinder) coveringTests(t *Tester, testBin, outputDir string, allTests []string, includeSubtests bool) ([]Result, error) {
+	return errGroupFinder{gate: (*Tester).acquire}.coveringTests(t, testBin, outputDir, allTests, includeSubtests)
+}
+
+func (p policyFinder) profiles(t *Tester, testBin, outputDir string, allTests []string) ([]TestProfile, error) {
+	return errGroupFinder{gate: (*Tester).acquire}.profiles(t, testBin, outputDir, allTests)
+}
+
+// runPolicy decides which tests may run concurrently, it is shared by the testers of dependent packages
+type runPolicy struct {
+	serial []*regexp.Regexp
+	groups map[string]*regexp.Regexp
+
+	exclusive sync.RWMutex // held for writing by serial tests and for reading by all others
+
+	mu         sync.Mutex
+	groupLocks map[string]*sync.Mutex
+}
+
+// newRunPolicy compiles the serial and group patterns of the config
+func newRunPolicy(conf Config) (*runPolicy, error) {
+	p := &runPolicy{
+		groups:     make(map[string]*regexp.Regexp, len(conf.Groups)),
+		groupLocks: make(map[string]*sync.Mutex),
+	}
+	for _, pattern := range conf.Serial {
+		exp, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid serial pattern: %w", err)
+		}
+		p.serial = append(p.serial, exp)
+	}
+	for name, pattern := range conf.Groups {
+		exp, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern of group '%s': %w", name, err)
+		}
+		p.groups[name] = exp
+	}
+	return p, nil
+}
+
+// groupLock returns the lock of the named group, creating it if needed
+func (p *runPolicy) groupLock(name string) *sync.Mutex {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.groupLocks[name] == nil {
+		p.groupLocks[name] = &sync.Mutex{}
+	}
+	return p.groupLocks[name]
+}
+
+// testPolicy returns whether a test must run serially and the groups it belongs to, in order of name
+// subtests follow the policy of their top-level test
+func (t *Tester) testPolicy(testName string) (bool, []string) {
+	top := strings.SplitN(testName, "/", 2)[0]
+	directives := t.directivesOf(top)
+
+	serial := directives.Serial
+	for _, exp := range t.policy.serial {
+		serial = serial || exp.MatchString(top)
+	}
+
+	inGroup := make(map[string]bool)
+	for _, name := range directives.Groups {
+		inGroup[name] = true
+	}
+	for name, exp := range t.policy.groups {
+		if exp.MatchString(top) {
+			inGroup[name] = true
+		}
+	}
+	groups := make([]string, 0, len(inGroup))
+	for name := range inGroup {
+		groups = append(groups, name)
+	}
+	sort.Strings(groups)
+	return serial, groups
+}
+
+// acquire waits until the policy allows the named test to run and returns the function which releases it
+func (t *Tester) acquire(testName string) func() {
+	if t.policy == nil {
+		return func() {}
+	}
+	p := t.policy
+	serial, groups := t.testPolicy(testName)
+	if serial {
+		p.exclusive.Lock()
+		return p.exclusive.Unlock
+	}
+
+	// group locks are always taken in order of name, so tests in several groups can't deadlock,
+	// and before the shared lock so that tests waiting on their group don't hold up serial tests
+	locks := make([]*sync.Mutex, len(groups))
+	for i, name := range groups {
+		locks[i] = p.groupLock(name)
+		locks[i].Lock()
+	}
+	p.exclusive.RLock()
+	return func() {
+		p.exclusive.RUnlock()
+		for i := len(locks) - 1; i >= 0; i-- {
+			locks[i].Unlock()
+		}
+	}
+}
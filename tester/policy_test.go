@@ -0,0 +1,107 @@
+package tester
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/ShawnROGrady/go-find-tests/finder"
+)
+
+var testPolicyTests = map[string]struct {
+	testName       string
+	expectedSerial bool
+	expectedGroups []string
+}{
+	"serial_directive": {
+		testName:       "TestSerial",
+		expectedSerial: true,
+		expectedGroups: []string{},
+	},
+	"serial_pattern": {
+		testName:       "TestGlobal",
+		expectedSerial: true,
+		expectedGroups: []string{},
+	},
+	"group_directive_and_pattern": {
+		testName:       "TestDBRead",
+		expectedGroups: []string{"db", "io"},
+	},
+	"subtest_follows_top_level_test": {
+		testName:       "TestCacheGet/miss",
+		expectedGroups: []string{"cache"},
+	},
+	"unrestricted": {
+		testName:       "TestUnitA",
+		expectedGroups: []string{},
+	},
+}
+
+func TestTestPolicy(t *testing.T) {
+	policy, err := newRunPolicy(Config{
+		Serial: []string{"^TestGlobal$"},
+		Groups: map[string]string{"cache": "^TestCache", "io": "^TestDB"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	tester := &Tester{
+		policy: policy,
+		directives: map[string]finder.Directives{
+			"TestSerial": {Serial: true},
+			"TestDBRead": {Groups: []string{"db"}},
+		},
+	}
+	for testName, test := range testPolicyTests {
+		serial, groups := tester.testPolicy(test.testName)
+		if serial != test.expectedSerial {
+			t.Errorf("Unexpected serial for %s (expected = %t, actual = %t)", testName, test.expectedSerial, serial)
+		}
+		if fmt.Sprint(groups) != fmt.Sprint(test.expectedGroups) {
+			t.Errorf("Unexpected groups for %s (expected = %v, actual = %v)", testName, test.expectedGroups, groups)
+		}
+	}
+}
+
+func TestNewRunPolicyInvalidPattern(t *testing.T) {
+	for name, conf := range map[string]Config{
+		"serial": {Serial: []string{"("}},
+		"group":  {Groups: map[string]string{"db": "("}},
+	} {
+		if _, err := newRunPolicy(conf); err == nil {
+			t.Errorf("Unexpectedly no error for invalid %s pattern", name)
+		}
+	}
+}
+
+func TestPolicyFinder(t *testing.T) {
+	dir, err := ioutil.TempDir("", "policy")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	defer os.RemoveAll(dir)
+	os.Setenv("POLICY_DIR", dir)
+	defer os.Unsetenv("POLICY_DIR")
+
+	tester, err := New("../testdata/policy/policy.go", 4, 0, Config{
+		Serial: []string{"^TestGlobal$"},
+		Groups: map[string]string{"cache": "^TestCache"},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error constructing tester: %s", err)
+	}
+	results, err := tester.Results()
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	if len(results) != 8 {
+		t.Errorf("Unexpected number of results (expected = 8, actual = %d)", len(results))
+	}
+	for _, r := range results {
+		if r.Status != StatusPass {
+			t.Errorf("%s ran concurrently with a conflicting test (status = %s)", r.Name, r.Status)
+		}
+	}
+}
@@ -1,8 +1,9 @@
 package tester
 
 import (
-	"fmt"
-	"os/exec"
+	"encoding/json"
+	"errors"
+	"os"
 	"path/filepath"
 	"strings"
 )
@@ -10,26 +11,107 @@ import (
 // position represents the position we want to test
 type position struct {
 	file, pkg string
+	dir       string    // absolute directory of the package
+	ws        workspace // module and workspace which own the package
 	line, col int
 }
 
-// setFilePkg sets the file and package from the provided path
+// setFilePkg sets the file, package, and package directory from the provided path
+//
+// the path may be absolute, relative to the working directory, relative to the root of the current module,
+// or qualified by the import path of the package (e.g. 'github.com/org/repo/pkg/file.go')
 func (p *position) setFilePkg(path string) error {
 	dir, file := filepath.Split(path)
-	pkg, err := packageName(dir)
-	if err != nil {
-		return fmt.Errorf("error finding go pkg from '%s': %s", path, err)
+	if err := p.setPkg(dir); err != nil {
+		return &PackageNotFoundError{Path: path, Err: err}
 	}
 	p.file = file
-	p.pkg = pkg
 	return nil
 }
 
-// packageName returns the go package name associated with the provided directory
-func packageName(dir string) (string, error) {
-	output, err := exec.Command("go", "list", dir).Output()
+// setPkg sets the package and package directory from the provided directory or import path
+func (p *position) setPkg(dir string) error {
+	pkg, ws, err := resolvePackage(dir)
 	if err != nil {
-		return "", parseCommandErr(err)
+		return err
+	}
+	p.pkg = pkg.ImportPath
+	p.dir = pkg.Dir
+	p.ws = ws
+	return nil
+}
+
+// listedPackage is the subset of the output of 'go list -json' used by the tester
+type listedPackage struct {
+	ImportPath string
+	Dir        string
+}
+
+// resolvePackage returns the go package associated with the provided directory
+// along with the module and workspace which own it
+func resolvePackage(dir string) (*listedPackage, workspace, error) {
+	if dir == "" {
+		dir = "."
 	}
-	return strings.TrimRight(string(output), "\n"), nil
+
+	if isDir(dir) {
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return nil, workspace{}, err
+		}
+		return listDir(abs)
+	}
+
+	if !filepath.IsAbs(dir) {
+		if root := findModRoot("."); root != "" {
+			if modRel := filepath.Join(root, dir); isDir(modRel) {
+				return listDir(modRel)
+			}
+		}
+	}
+
+	// fall back to treating the directory as an import path, resolved by the module of the working directory
+	wd, err := os.Getwd()
+	if err != nil {
+		return nil, workspace{}, err
+	}
+	ws, err := findWorkspace(wd)
+	if err != nil {
+		return nil, workspace{}, err
+	}
+	pkg, err := listPackage(ws, strings.TrimSuffix(filepath.ToSlash(dir), "/"))
+	return pkg, ws, err
+}
+
+// listDir lists the package in the provided absolute directory using the module and workspace which own it
+func listDir(dir string) (*listedPackage, workspace, error) {
+	ws, err := findWorkspace(dir)
+	if err != nil {
+		return nil, workspace{}, err
+	}
+	pkg, err := listPackage(ws, dir)
+	return pkg, ws, err
+}
+
+// listPackage runs 'go list -json' for the provided package
+func listPackage(ws workspace, pkg string) (*listedPackage, error) {
+	cmd := ws.command("list", "-json", pkg)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, parseCommandErr(cmd, err)
+	}
+
+	listed := &listedPackage{}
+	if err := json.Unmarshal(output, listed); err != nil {
+		return nil, err
+	}
+	if listed.Dir == "" {
+		return nil, errors.New("go list returned no directory for " + pkg)
+	}
+	return listed, nil
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
 }
@@ -1,10 +1,15 @@
 package tester
 
-import "testing"
+import (
+	"path/filepath"
+	"testing"
+)
 
 var setFilePkgTests = map[string]struct {
 	path                      string
+	absPath                   bool // path should be made absolute before calling setFilePkg
 	expectedFile, expectedPkg string
+	expectedDir               string // relative to the working directory, unchecked if empty
 	expectErr                 bool
 }{
 	"std_lib_file": {
@@ -17,8 +22,52 @@ var setFilePkgTests = map[string]struct {
 		path:         "./package_test.go",
 		expectedFile: "package_test.go",
 		expectedPkg:  "github.com/ShawnROGrady/go-find-tests/tester",
+		expectedDir:  ".",
 		expectErr:    false,
 	},
+	"no_dir": {
+		path:         "position.go",
+		expectedFile: "position.go",
+		expectedPkg:  "github.com/ShawnROGrady/go-find-tests/tester",
+		expectedDir:  ".",
+	},
+	"absolute_path": {
+		path:         "../cover/profile.go",
+		absPath:      true,
+		expectedFile: "profile.go",
+		expectedPkg:  "github.com/ShawnROGrady/go-find-tests/cover",
+		expectedDir:  "../cover",
+	},
+	"outside_working_dir": {
+		path:         "../testdata/size/size.go",
+		expectedFile: "size.go",
+		expectedPkg:  "github.com/ShawnROGrady/go-find-tests/testdata/size",
+		expectedDir:  "../testdata/size",
+	},
+	"module_relative": {
+		path:         "cover/profile.go",
+		expectedFile: "profile.go",
+		expectedPkg:  "github.com/ShawnROGrady/go-find-tests/cover",
+		expectedDir:  "../cover",
+	},
+	"import_path_qualified": {
+		path:         "github.com/ShawnROGrady/go-find-tests/finder/finder.go",
+		expectedFile: "finder.go",
+		expectedPkg:  "github.com/ShawnROGrady/go-find-tests/finder",
+		expectedDir:  "../finder",
+	},
+	"nested_module": {
+		path:         "../testdata/nested/double.go",
+		expectedFile: "double.go",
+		expectedPkg:  "example.com/nested",
+		expectedDir:  "../testdata/nested",
+	},
+	"workspace_module": {
+		path:         "../testdata/workspace/b/abs.go",
+		expectedFile: "abs.go",
+		expectedPkg:  "example.com/b",
+		expectedDir:  "../testdata/workspace/b",
+	},
 	"invalid_file": {
 		path:      "./bad/path/to/file.go",
 		expectErr: true,
@@ -30,7 +79,16 @@ func TestSetFilePkg(t *testing.T) {
 		t.Run(testName, func(t *testing.T) {
 			p := &position{}
 
-			err := p.setFilePkg(test.path)
+			path := test.path
+			if test.absPath {
+				abs, err := filepath.Abs(path)
+				if err != nil {
+					t.Fatalf("Unexpected err: %s", err)
+				}
+				path = abs
+			}
+
+			err := p.setFilePkg(path)
 			if err != nil {
 				if test.expectErr {
 					return
@@ -48,6 +106,15 @@ func TestSetFilePkg(t *testing.T) {
 			if test.expectedPkg != p.pkg {
 				t.Errorf("Unexpected pkg (expected = '%s', actual = '%s')", test.expectedPkg, p.pkg)
 			}
+			if test.expectedDir != "" {
+				expectedDir, err := filepath.Abs(test.expectedDir)
+				if err != nil {
+					t.Fatalf("Unexpected err: %s", err)
+				}
+				if expectedDir != p.dir {
+					t.Errorf("Unexpected dir (expected = '%s', actual = '%s')", expectedDir, p.dir)
+				}
+			}
 		})
 	}
 }
@@ -0,0 +1,131 @@
+package tester
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/ShawnROGrady/go-find-tests/cover"
+)
+
+// RedundantTest is a test whose coverage is retained by other tests of the suite
+type RedundantTest struct {
+	Test       TestProfile
+	Statements int // statements covered by the test
+	// CoveredBy are the remaining tests which together cover every statement of the test, in the order they were chosen.
+	// A single test if any covers all of them on its own, empty if the test covers no statements
+	CoveredBy []string
+	Duplicate bool // whether the single test of CoveredBy covers exactly the same blocks
+}
+
+// Redundant returns the tests whose coverage is a subset of that of a single other test, or of the union of others,
+// ordered by elapsed time from the slowest
+//
+// tests are removed greedily from the slowest, each compared only with the tests which haven't been removed,
+// so every reported test can be removed without losing coverage. A test is never compared with its subtests or parents,
+// which always overlap it, and removing a test also removes its subtests. Once a subtest is removed the profiles of its
+// parents still include its coverage, so they are no longer compared with any test
+func Redundant(profiles []TestProfile) []RedundantTest {
+	order := make([]int, len(profiles))
+	sets := make([]cover.Set, len(profiles))
+	for i := range profiles {
+		order[i] = i
+		sets[i] = profiles[i].Profile.Covered()
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		a, b := profiles[order[i]], profiles[order[j]]
+		if a.Elapsed != b.Elapsed {
+			return a.Elapsed > b.Elapsed
+		}
+		return a.Name < b.Name
+	})
+
+	var (
+		removed   = make([]bool, len(profiles))
+		inflated  = make([]bool, len(profiles)) // a subtest was removed, see above
+		redundant = []RedundantTest{}
+	)
+	for _, i := range order {
+		if removed[i] {
+			continue
+		}
+		var (
+			others = []int{}
+			union  = make(cover.Set)
+		)
+		for j := range profiles {
+			if removed[j] || inflated[j] || related(profiles[i].Name, profiles[j].Name) {
+				continue
+			}
+			others = append(others, j)
+			union = union.Union(sets[j])
+		}
+		if len(sets[i].Difference(union)) != 0 {
+			continue
+		}
+
+		r := RedundantTest{Test: profiles[i], Statements: sets[i].Statements(), CoveredBy: []string{}}
+		if len(sets[i]) != 0 {
+			r.CoveredBy, r.Duplicate = retainingTests(profiles, sets, i, others)
+		}
+		redundant = append(redundant, r)
+		for j := range profiles {
+			switch {
+			case j == i || strings.HasPrefix(profiles[j].Name, profiles[i].Name+"/"):
+				removed[j] = true
+			case strings.HasPrefix(profiles[i].Name, profiles[j].Name+"/"):
+				inflated[j] = true
+			}
+		}
+	}
+	return redundant
+}
+
+// related returns whether either test is the other or one of its subtests
+func related(a, b string) bool {
+	return a == b || strings.HasPrefix(a, b+"/") || strings.HasPrefix(b, a+"/")
+}
+
+// retainingTests returns the tests of candidates which cover every block of the test, and whether they are an exact duplicate
+// a single test is preferred, an exact duplicate over any other and then the fastest,
+// otherwise tests are chosen greedily by the number of newly covered statements per unit of elapsed time
+func retainingTests(profiles []TestProfile, sets []cover.Set, test int, candidates []int) ([]string, bool) {
+	var (
+		single    = -1
+		duplicate bool
+	)
+	for _, j := range candidates {
+		if len(sets[test].Difference(sets[j])) != 0 {
+			continue
+		}
+		equal := len(sets[j]) == len(sets[test])
+		if single == -1 || (equal && !duplicate) || (equal == duplicate && cost(profiles[j]) < cost(profiles[single])) {
+			single, duplicate = j, equal
+		}
+	}
+	if single != -1 {
+		return []string{profiles[single].Name}, duplicate
+	}
+
+	var (
+		chosen    = []string{}
+		remaining = sets[test]
+	)
+	for len(remaining) != 0 {
+		best, bestGain := -1, 0
+		for _, j := range candidates {
+			gain := sets[j].Intersect(remaining).Statements()
+			if gain == 0 {
+				continue
+			}
+			if best == -1 || betterCover(gain, cost(profiles[j]), profiles[j].Name, bestGain, cost(profiles[best]), profiles[best].Name) {
+				best, bestGain = j, gain
+			}
+		}
+		if best == -1 {
+			break
+		}
+		chosen = append(chosen, profiles[best].Name)
+		remaining = remaining.Difference(sets[best])
+	}
+	return chosen, false
+}
@@ -0,0 +1,95 @@
+package tester
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestRedundant(t *testing.T) {
+	var redundantTests = map[string]struct {
+		profiles []TestProfile
+		expected []string // name, covering tests, and whether a duplicate of each redundant test
+	}{
+		"union_of_others": {
+			profiles: []TestProfile{
+				minimizeProfile(t, "TestAll", time.Second, 1, 1, 1, 1),
+				minimizeProfile(t, "TestFirstHalf", 100*time.Millisecond, 1, 1, 1, 0),
+				minimizeProfile(t, "TestSecondHalf", 100*time.Millisecond, 0, 0, 1, 1),
+			},
+			expected: []string{"TestAll [TestFirstHalf TestSecondHalf] false"},
+		},
+		"duplicate_subtests": {
+			profiles: []TestProfile{
+				minimizeProfile(t, "TestTable", 300*time.Millisecond, 1, 1, 0, 0),
+				minimizeProfile(t, "TestTable/a", 100*time.Millisecond, 1, 0, 0, 0),
+				minimizeProfile(t, "TestTable/b", 100*time.Millisecond, 1, 0, 0, 0),
+				minimizeProfile(t, "TestTable/c", 100*time.Millisecond, 0, 1, 0, 0),
+				minimizeProfile(t, "TestOther", 50*time.Millisecond, 1, 0, 0, 0),
+			},
+			// TestTable still includes the coverage of its removed subtests, so can't retain TestOther
+			expected: []string{"TestTable/a [TestOther] true", "TestTable/b [TestOther] true"},
+		},
+		"subset_of_single": {
+			profiles: []TestProfile{
+				minimizeProfile(t, "TestParent", time.Second, 1, 0, 0, 0),
+				minimizeProfile(t, "TestParent/sub", 10*time.Millisecond, 1, 0, 0, 0),
+				minimizeProfile(t, "TestBoth", 30*time.Millisecond, 1, 1, 0, 0),
+				minimizeProfile(t, "TestFirst", 20*time.Millisecond, 1, 0, 0, 0),
+				minimizeProfile(t, "TestNothing", 10*time.Millisecond, 0, 0, 0, 0),
+			},
+			// removing TestParent removes its subtest, TestFirst is preferred as a duplicate
+			expected: []string{"TestParent [TestFirst] true", "TestFirst [TestBoth] false", "TestNothing [] false"},
+		},
+	}
+
+	for testName, test := range redundantTests {
+		t.Run(testName, func(t *testing.T) {
+			redundant := Redundant(test.profiles)
+
+			actual := make([]string, len(redundant))
+			for i, r := range redundant {
+				actual[i] = fmt.Sprintf("%s %v %t", r.Test.Name, r.CoveredBy, r.Duplicate)
+			}
+			if fmt.Sprint(actual) != fmt.Sprint(test.expected) {
+				t.Errorf("Unexpected redundant tests (expected = %q, actual = %q)", test.expected, actual)
+			}
+		})
+	}
+}
+
+func TestRedundantSubtests(t *testing.T) {
+	tester, err := NewPackage("../testdata/redundant", Config{IncludeSubtests: true})
+	if err != nil {
+		t.Fatalf("Unexpected error constructing tester: %s", err)
+	}
+
+	profiles, err := tester.Profiles()
+	if err != nil {
+		t.Fatalf("Unexpected error collecting profiles: %s", err)
+	}
+	names := make(map[string]bool, len(profiles))
+	for _, prof := range profiles {
+		names[prof.Name] = true
+	}
+	for _, name := range []string{"TestSign", "TestPositive", "TestSign/negative", "TestSign/zero", "TestSign/positive", "TestSign/also_positive"} {
+		if !names[name] {
+			t.Errorf("Missing profile of %s", name)
+		}
+	}
+	if len(profiles) != 6 {
+		t.Errorf("Unexpected number of profiles (expected = 6, actual = %d)", len(profiles))
+	}
+
+	// only one of the tests of positive numbers is needed, which one depends on how long each took
+	positive := map[string]bool{"TestPositive": true, "TestSign/positive": true, "TestSign/also_positive": true}
+	redundant := Redundant(profiles)
+	if len(redundant) != 2 {
+		t.Errorf("Unexpected number of redundant tests (expected = 2, actual = %d)", len(redundant))
+	}
+	for _, r := range redundant {
+		if !positive[r.Test.Name] || len(r.CoveredBy) != 1 {
+			t.Errorf("Unexpected redundant test %s covered by %v", r.Test.Name, r.CoveredBy)
+		}
+	}
+}
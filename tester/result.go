@@ -0,0 +1,141 @@
+package tester
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/ShawnROGrady/go-find-tests/cover"
+	"github.com/ShawnROGrady/go-find-tests/finder"
+)
+
+// Kind is the kind of function a test was declared as
+type Kind string
+
+// Kinds of test functions recognized by 'go test'
+const (
+	KindTest      Kind = "test"
+	KindExample   Kind = "example"
+	KindBenchmark Kind = "benchmark"
+	KindFuzz      Kind = "fuzz"
+)
+
+// kindOf returns the kind of a test based on the name of its top-level function
+func kindOf(testName string) Kind {
+	top := strings.SplitN(testName, "/", 2)[0]
+	switch {
+	case strings.HasPrefix(top, "Example"):
+		return KindExample
+	case strings.HasPrefix(top, "Benchmark"):
+		return KindBenchmark
+	case strings.HasPrefix(top, "Fuzz"):
+		return KindFuzz
+	}
+	return KindTest
+}
+
+// Status is the final status of a test run
+type Status string
+
+// Statuses reported by test2json
+const (
+	StatusPass Status = "pass"
+	StatusSkip Status = "skip"
+	StatusFail Status = "fail"
+)
+
+// Result represents a test which covers the provided position
+type Result struct {
+	Package  string               `json:"package"`
+	Name     string               `json:"name"`
+	Parent   string               `json:"parent,omitempty"`   // the test which ran this one if it is a subtest
+	Children []string             `json:"children,omitempty"` // covering subtests run directly by this test
+	Subtests []string             `json:"subtests,omitempty"` // all passing (or included failing and skipped) subtests run by this test, only set for top-level tests when including subtests
+	Kind     Kind                 `json:"kind"`
+	Position *finder.TestPosition `json:"position,omitempty"` // declaration of the test, nil for subtests
+	Elapsed  time.Duration        `json:"elapsed"`
+	Status   Status               `json:"status"`
+	Block    cover.Block          `json:"block"` // the block containing the position, Count is the number of times it was hit
+
+	Statements int `json:"statements"`           // total statements covered by the run
+	SetupHits  int `json:"setup_hits,omitempty"` // times package initialization or TestMain alone hit the block, excluded from its Count
+
+	Runs        int `json:"runs"`         // number of times the test was run
+	CoveredRuns int `json:"covered_runs"` // number of runs which covered the position
+}
+
+// Hits returns the number of times the run executed the block containing the position
+func (r Result) Hits() int {
+	return r.Block.Count
+}
+
+// Unstable returns whether the test covered the position in only some of its runs
+func (r Result) Unstable() bool {
+	return r.CoveredRuns < r.Runs
+}
+
+// TestProfile is the cover profile of a single run of a test, which is a top-level test unless including subtests
+type TestProfile struct {
+	Package string
+	Name    string
+	Kind    Kind
+	Elapsed time.Duration
+	Status  Status
+	Profile *cover.Profile
+
+	subtests []string // subtests run by a top-level test, only set when including subtests
+}
+
+// testOutcome returns the final status and elapsed time of the named test from the test2json output of its run
+func testOutcome(r io.Reader, testName string) (Status, time.Duration, error) {
+	var (
+		scanner = bufio.NewScanner(r)
+		status  = StatusPass
+		elapsed time.Duration
+	)
+
+	for scanner.Scan() {
+		event := TestEvent{}
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			return "", 0, err
+		}
+		if event.Test != testName {
+			continue
+		}
+
+		switch Status(event.Action) {
+		case StatusPass, StatusSkip, StatusFail:
+			status = Status(event.Action)
+			elapsed = time.Duration(event.Elapsed * float64(time.Second))
+		}
+	}
+	return status, elapsed, nil
+}
+
+// linkResults sets the package, kind, position, parent, and children of each result
+func linkResults(results []Result, pkg string, positions map[string]finder.TestPosition) {
+	index := make(map[string]int, len(results))
+	for i := range results {
+		index[results[i].Name] = i
+	}
+
+	for i := range results {
+		results[i].Package = pkg
+		results[i].Kind = kindOf(results[i].Name)
+		if pos, ok := positions[results[i].Name]; ok {
+			pos := pos
+			results[i].Position = &pos
+		}
+
+		sep := strings.LastIndex(results[i].Name, "/")
+		if sep == -1 {
+			continue
+		}
+		results[i].Parent = results[i].Name[:sep]
+		if parent, ok := index[results[i].Parent]; ok {
+			results[parent].Children = append(results[parent].Children, results[i].Name)
+		}
+	}
+}
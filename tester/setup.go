@@ -0,0 +1,99 @@
+package tester
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ShawnROGrady/go-find-tests/cover"
+)
+
+// baselineTest is the name under which the test binary is run without running any test
+// it can't be the name of a test since it lacks the prefix of a test function
+const baselineTest = "baseline"
+
+// runBaseline runs the test binary without running any test, recording the coverage of package initialization and TestMain
+// it is only needed to find the tests which cover a position, and only run if that coverage is excluded from each test
+func (t *Tester) runBaseline(testBin, outputDir string) error {
+	t.setup = nil
+	if t.testPos.file == "" || t.includeSetup {
+		return nil
+	}
+	prof, _, err := t.profile(baselineTest, testBin, outputDir)
+	if err != nil {
+		return fmt.Errorf("error running package setup: %w", err)
+	}
+	t.setup = prof
+	return nil
+}
+
+// SetupProfile runs the package without running any test and returns the coverage of package initialization and TestMain
+// its blocks are every instrumented statement of the package, so it describes packages without any test as well,
+// though without test files there is no test binary to run and every block has a count of zero
+func (t *Tester) SetupProfile() (*cover.Profile, error) {
+	outputDir, cleanup, err := t.outputDir()
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	testBin, _, err := t.suite(outputDir)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(testBin); err != nil {
+		// 'go test -c' writes no binary for a package without test files
+		return t.goTestSetup(outputDir)
+	}
+	prof, _, err := t.profile(baselineTest, testBin, outputDir)
+	if err != nil {
+		return nil, fmt.Errorf("error running package setup: %w", err)
+	}
+	return prof, nil
+}
+
+// goTestSetup runs 'go test' without running any test, which still reports the coverage of a package without test files
+func (t *Tester) goTestSetup(outputDir string) (*cover.Profile, error) {
+	pathToCover := filepath.Join(outputDir, coverFileName(baselineTest))
+	cmdArgs := []string{"test", t.testPos.pkg, "-covermode", "count", "-run", "^$", "-coverprofile", pathToCover}
+	if t.coverPkg != "" {
+		cmdArgs = append(cmdArgs, "-coverpkg", t.coverPkg)
+	}
+	cmd := t.ws.command(cmdArgs...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, parseCommandErr(cmd, err)
+	}
+
+	f, err := os.Open(pathToCover)
+	if err != nil {
+		return nil, &ProfileError{Test: baselineTest, Path: pathToCover, Err: err}
+	}
+	defer f.Close()
+	prof, err := cover.New(f)
+	if err != nil {
+		return nil, &ProfileError{Test: baselineTest, Path: pathToCover, Err: err}
+	}
+	return prof, nil
+}
+
+// CoveredBySetup returns the block containing the position if it is covered without running any test,
+// i.e. by package initialization (init functions and package level variables) or TestMain
+// it is only known once the covering tests have been found, e.g. by CoveredBy or Results, and never with IncludeSetup
+func (t *Tester) CoveredBySetup() (cover.Block, bool) {
+	if t.setup == nil {
+		return cover.Block{}, false
+	}
+	return t.setup.CoveringBlock(t.testPos.file, t.testPos.line, t.testPos.col)
+}
+
+// withoutSetup returns the profile of a test excluding the coverage of package initialization and TestMain
+// unless it was configured to be included
+func (t *Tester) withoutSetup(prof *cover.Profile) *cover.Profile {
+	if t.setup == nil {
+		return prof
+	}
+	return prof.Subtract(t.setup)
+}
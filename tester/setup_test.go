@@ -0,0 +1,90 @@
+package tester
+
+import (
+	"fmt"
+	"testing"
+)
+
+var setupTests = map[string]struct {
+	line            int
+	includeSetup    bool
+	expectCoveredBy string
+	expectSetup     bool
+}{
+	"package_variable_initializer": {
+		line:            6,
+		expectCoveredBy: "[TestRebuild]",
+		expectSetup:     true,
+	},
+	"package_variable_initializer_including_setup": {
+		// the baseline is only run when its coverage is excluded, so setup coverage isn't known
+		line:            6,
+		includeSetup:    true,
+		expectCoveredBy: "[TestLookup TestRebuild TestOther]",
+	},
+	"test_main": {
+		line:            10,
+		expectCoveredBy: "[TestLookup]",
+		expectSetup:     true,
+	},
+	"not_covered_by_setup": {
+		line:            14,
+		expectCoveredBy: "[TestOther]",
+	},
+}
+
+func TestSetupCoverage(t *testing.T) {
+	for testName, test := range setupTests {
+		test := test
+		t.Run(testName, func(t *testing.T) {
+			tester, err := New("../testdata/setup/setup.go", test.line, 0, Config{IncludeSetup: test.includeSetup})
+			if err != nil {
+				t.Fatalf("Unexpected error constructing tester: %s", err)
+			}
+			results, err := tester.Results()
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			coveredBy := make([]string, len(results))
+			for i := range results {
+				coveredBy[i] = results[i].Name
+			}
+			if fmt.Sprint(coveredBy) != test.expectCoveredBy {
+				t.Errorf("Unexpected covering tests (expected = %s, actual = %v)", test.expectCoveredBy, coveredBy)
+			}
+			block, setup := tester.CoveredBySetup()
+			if setup != test.expectSetup {
+				t.Errorf("Unexpected covered by setup (expected = %t, actual = %t)", test.expectSetup, setup)
+			}
+			for _, result := range results {
+				if result.SetupHits != block.Count {
+					t.Errorf("Unexpected %s setup hits (expected = %d, actual = %d)", result.Name, block.Count, result.SetupHits)
+				}
+			}
+		})
+	}
+}
+
+func TestSetupProfile(t *testing.T) {
+	// a package without test files has no test binary to run, while one with only helpers does
+	for pkg, expected := range map[string]string{
+		"../testdata/notests": "[notests.go:6 0 notests.go:10 0 notests.go:11 0 notests.go:13 0]",
+		"../testdata/nocases": "[nocases.go:4 0]",
+	} {
+		tester, err := NewPackage(pkg, Config{})
+		if err != nil {
+			t.Fatalf("Unexpected error constructing tester: %s", err)
+		}
+		prof, err := tester.SetupProfile()
+		if err != nil {
+			t.Fatalf("Unexpected error running %s: %s", pkg, err)
+		}
+		actual := []string{}
+		for _, b := range prof.Blocks() {
+			actual = append(actual, fmt.Sprintf("%s:%d %d", b.File, b.StartLine, b.Count))
+		}
+		if fmt.Sprint(actual) != expected {
+			t.Errorf("Unexpected blocks of %s (expected = %s, actual = %v)", pkg, expected, actual)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package tester
+
+import (
+	"fmt"
+	"sort"
+)
+
+// SortOrder determines the order of covering test results
+type SortOrder string
+
+// Supported sort orders
+const (
+	SortName        SortOrder = "name"        // alphabetically by test name
+	SortDuration    SortOrder = "duration"    // fastest first
+	SortSpecificity SortOrder = "specificity" // fewest total statements covered first
+	SortHits        SortOrder = "hits"        // most hits of the position first
+)
+
+// ParseSortOrder parses the name of a sort order
+func ParseSortOrder(s string) (SortOrder, error) {
+	switch order := SortOrder(s); order {
+	case SortName, SortDuration, SortSpecificity, SortHits:
+		return order, nil
+	}
+	return "", fmt.Errorf("unknown sort order '%s' (expected one of: %s, %s, %s, %s)", s, SortName, SortDuration, SortSpecificity, SortHits)
+}
+
+// Sort orders the results in place, ties are broken by test name
+func Sort(results []Result, order SortOrder) {
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		switch order {
+		case SortDuration:
+			if a.Elapsed != b.Elapsed {
+				return a.Elapsed < b.Elapsed
+			}
+		case SortSpecificity:
+			if a.Statements != b.Statements {
+				return a.Statements < b.Statements
+			}
+		case SortHits:
+			if a.Hits() != b.Hits() {
+				return a.Hits() > b.Hits()
+			}
+		}
+		return a.Name < b.Name
+	})
+}
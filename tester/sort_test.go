@@ -0,0 +1,55 @@
+package tester
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ShawnROGrady/go-find-tests/cover"
+)
+
+var sortResults = []Result{
+	{Name: "TestB", Elapsed: 2 * time.Millisecond, Statements: 5, Block: cover.Block{Count: 1}},
+	{Name: "TestA", Elapsed: 3 * time.Millisecond, Statements: 5, Block: cover.Block{Count: 7}},
+	{Name: "TestC", Elapsed: time.Millisecond, Statements: 40, Block: cover.Block{Count: 2}},
+	{Name: "TestD", Elapsed: 2 * time.Millisecond, Statements: 3, Block: cover.Block{Count: 1}},
+}
+
+var sortTests = map[SortOrder][]string{
+	SortName:        {"TestA", "TestB", "TestC", "TestD"},
+	SortDuration:    {"TestC", "TestB", "TestD", "TestA"},
+	SortSpecificity: {"TestD", "TestA", "TestB", "TestC"},
+	SortHits:        {"TestA", "TestC", "TestB", "TestD"},
+}
+
+func TestSort(t *testing.T) {
+	for order, expectedNames := range sortTests {
+		t.Run(string(order), func(t *testing.T) {
+			results := make([]Result, len(sortResults))
+			copy(results, sortResults)
+			Sort(results, order)
+
+			names := make([]string, len(results))
+			for i := range results {
+				names[i] = results[i].Name
+			}
+			if fmt.Sprint(names) != fmt.Sprint(expectedNames) {
+				t.Errorf("Unexpected order (expected = %v, actual = %v)", expectedNames, names)
+			}
+		})
+	}
+}
+
+func TestParseSortOrder(t *testing.T) {
+	for order := range sortTests {
+		parsed, err := ParseSortOrder(string(order))
+		if err != nil {
+			t.Errorf("Unexpected error parsing '%s': %s", order, err)
+		} else if parsed != order {
+			t.Errorf("Unexpected order (expected = %s, actual = %s)", order, parsed)
+		}
+	}
+	if _, err := ParseSortOrder("fastest"); err == nil {
+		t.Errorf("Unexpectedly parsed unknown sort order")
+	}
+}
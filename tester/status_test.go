@@ -0,0 +1,75 @@
+package tester
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+var statusTests = map[string]struct {
+	run            string
+	includeFailing bool
+	includeSkipped bool
+	expectErrIs    error
+	expectResults  string
+}{
+	"skipped_excluded": {
+		run:           "TestSkipped|TestPasses",
+		expectResults: "[TestPasses:pass]",
+	},
+	"skipped_included": {
+		run:            "TestSkipped|TestPasses",
+		includeSkipped: true,
+		expectResults:  "[TestSkipped:skip TestPasses:pass]",
+	},
+	"failing_subtest_is_error": {
+		run:         "TestStatus",
+		expectErrIs: ErrTestFailed,
+	},
+	"failing_included": {
+		run:            "TestStatus",
+		includeFailing: true,
+		expectResults:  "[TestStatus:fail TestStatus/passes:pass TestStatus/fails:fail]",
+	},
+	"failing_and_skipped_included": {
+		run:            "TestStatus",
+		includeFailing: true,
+		includeSkipped: true,
+		expectResults:  "[TestStatus:fail TestStatus/passes:pass TestStatus/fails:fail TestStatus/skips:skip]",
+	},
+}
+
+func TestResultStatus(t *testing.T) {
+	for testName, test := range statusTests {
+		test := test
+		t.Run(testName, func(t *testing.T) {
+			tester, err := New("../testdata/status/status.go", 4, 0, Config{
+				Run:             test.run,
+				IncludeSubtests: true,
+				IncludeFailing:  test.includeFailing,
+				IncludeSkipped:  test.includeSkipped,
+			})
+			if err != nil {
+				t.Fatalf("Unexpected error constructing tester: %s", err)
+			}
+			results, err := tester.Results()
+			if test.expectErrIs != nil {
+				if !errors.Is(err, test.expectErrIs) {
+					t.Errorf("Unexpected error (expected = %v, actual = %v)", test.expectErrIs, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+
+			actual := []string{}
+			for _, r := range results {
+				actual = append(actual, fmt.Sprintf("%s:%s", r.Name, r.Status))
+			}
+			if fmt.Sprint(actual) != test.expectResults {
+				t.Errorf("Unexpected results (expected = %s, actual = %v)", test.expectResults, actual)
+			}
+		})
+	}
+}
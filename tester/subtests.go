@@ -19,9 +19,16 @@ type TestEvent struct {
 	Output  string
 }
 
-func subtests(r io.Reader) ([]string, error) {
+// subtest is a subtest run by a test along with its final status
+type subtest struct {
+	name   string
+	status Status
+}
+
+// subtests returns every subtest in the test2json output of a run, in the order they finished
+func subtests(r io.Reader) ([]subtest, error) {
 	var (
-		subtests = []string{}
+		subtests = []subtest{}
 		scanner  = bufio.NewScanner(r)
 	)
 
@@ -32,9 +39,25 @@ func subtests(r io.Reader) ([]string, error) {
 		}
 
 		// TODO: should be able to distinguish different levels of sub tests
-		if event.Action == "pass" && strings.Contains(event.Test, "/") {
-			subtests = append(subtests, event.Test)
+		if !strings.Contains(event.Test, "/") {
+			continue
+		}
+		switch status := Status(event.Action); status {
+		case StatusPass, StatusFail, StatusSkip:
+			subtests = append(subtests, subtest{name: event.Test, status: status})
 		}
 	}
 	return subtests, nil
 }
+
+// considered returns whether a test run with the status can cover a position
+// passing runs always can, failing and skipped runs only if configured to include them
+func (t *Tester) considered(status Status) bool {
+	switch status {
+	case StatusFail:
+		return t.includeFailing
+	case StatusSkip:
+		return t.includeSkipped
+	}
+	return true
+}
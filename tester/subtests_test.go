@@ -7,7 +7,7 @@ import (
 
 var subtestsTests = map[string]struct {
 	testOutput       string
-	expectedSubtests []string
+	expectedSubtests []subtest
 }{
 	"3_subtests": {
 		testOutput: `{"Time":"2019-11-10T18:10:33.350927-06:00","Action":"run","Package":"github.com/ShawnROGrady/go-find-tests/testdata/subtests","Test":"TestIsEmpty"}
@@ -30,7 +30,11 @@ var subtestsTests = map[string]struct {
 {"Time":"2019-11-10T18:10:33.353601-06:00","Action":"output","Package":"github.com/ShawnROGrady/go-find-tests/testdata/subtests","Output":"ok  \tgithub.com/ShawnROGrady/go-find-tests/testdata/subtests\t0.008s\n"}
 {"Time":"2019-11-10T18:10:33.353614-06:00","Action":"pass","Package":"github.com/ShawnROGrady/go-find-tests/testdata/subtests","Elapsed":0.008}
 `,
-		expectedSubtests: []string{"TestIsEmpty/empty_input", "TestIsEmpty/short_input", "TestIsEmpty/long_input"},
+		expectedSubtests: []subtest{
+			{name: "TestIsEmpty/empty_input", status: StatusPass},
+			{name: "TestIsEmpty/short_input", status: StatusPass},
+			{name: "TestIsEmpty/long_input", status: StatusPass},
+		},
 	},
 	"no_subtests": {
 		testOutput: `{"Time":"2019-11-10T18:09:29.637515-06:00","Action":"run","Package":"github.com/ShawnROGrady/go-find-tests/testdata/len10","Test":"TestEmptyStringIsEmpty"}
@@ -41,7 +45,25 @@ var subtestsTests = map[string]struct {
 {"Time":"2019-11-10T18:09:29.637954-06:00","Action":"output","Package":"github.com/ShawnROGrady/go-find-tests/testdata/len10","Output":"ok  \tgithub.com/ShawnROGrady/go-find-tests/testdata/len10\t0.006s\n"}
 {"Time":"2019-11-10T18:09:29.637968-06:00","Action":"pass","Package":"github.com/ShawnROGrady/go-find-tests/testdata/len10","Elapsed":0.006}
 `,
-		expectedSubtests: []string{},
+		expectedSubtests: []subtest{},
+	},
+	"failing_and_skipped_subtests": {
+		testOutput: `{"Action":"run","Test":"TestStatus"}
+{"Action":"run","Test":"TestStatus/passes"}
+{"Action":"run","Test":"TestStatus/fails"}
+{"Action":"run","Test":"TestStatus/skips"}
+{"Action":"output","Test":"TestStatus/fails","Output":"    status_test.go:10: failed\n"}
+{"Action":"pass","Test":"TestStatus/passes","Elapsed":0}
+{"Action":"fail","Test":"TestStatus/fails","Elapsed":0}
+{"Action":"skip","Test":"TestStatus/skips","Elapsed":0}
+{"Action":"fail","Test":"TestStatus","Elapsed":0}
+{"Action":"fail","Elapsed":0.008}
+`,
+		expectedSubtests: []subtest{
+			{name: "TestStatus/passes", status: StatusPass},
+			{name: "TestStatus/fails", status: StatusFail},
+			{name: "TestStatus/skips", status: StatusSkip},
+		},
 	},
 }
 
@@ -57,12 +79,12 @@ func TestSubtests(t *testing.T) {
 			}
 
 			if len(subtests) != len(testCase.expectedSubtests) {
-				t.Fatalf("Unexpected subtests [expected = %q, actual = %q]", testCase.expectedSubtests, subtests)
+				t.Fatalf("Unexpected subtests [expected = %v, actual = %v]", testCase.expectedSubtests, subtests)
 			}
 
 			for i := range subtests {
 				if subtests[i] != testCase.expectedSubtests[i] {
-					t.Errorf("Unexpected subtests[%d] [expected = '%s', actual ='%s']", i, testCase.expectedSubtests[i], subtests[i])
+					t.Errorf("Unexpected subtests[%d] [expected = '%v', actual ='%v']", i, testCase.expectedSubtests[i], subtests[i])
 				}
 			}
 		})
@@ -2,13 +2,18 @@ package tester
 
 import (
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
-	"io/ioutil"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"strings"
+	"time"
+
+	"github.com/ShawnROGrady/go-find-tests/cover"
+	"github.com/ShawnROGrady/go-find-tests/finder"
 )
 
 // Tester performs the main testing logic
@@ -16,17 +21,78 @@ type Tester struct {
 	testPos         position
 	includeSubtests bool
 	short           bool
-	run             string
-	dir             string // directory of test
+	run             string // '/' separated levels, see runPattern
+	skip            string
+	timeout         time.Duration
+	dir             string    // directory of test
+	ws              workspace // module and workspace which own the tested package
+	coverPkg        string    // if set, sets '-coverpkg' when compiling tests
+	workspaceDeps   bool
+	benchmarks      bool
+	runs            int // number of times each test is run, see runCount
+	isolate         bool
+	copyPackage     bool
+	env             []string // additional environment of each test process
+	goEnv           []string // go environment preserved when isolating tests, see prepareEnv
+	policy          *runPolicy
+	keep            *keeper // if set the artifacts of each test are kept, see Config.KeepDir
+	includeSetup    bool
+	includeFailing  bool
+	includeSkipped  bool
+	execWrapper     []string                     // program and arguments which run the test binary, see Config.ExecWrapper
+	setup           *cover.Profile               // coverage of running the test binary without running any test, see runBaseline
+	directives      map[string]finder.Directives // directives of the tests of the package, see loadDirectives
 	coverFinder     coverFinder
 }
 
 // Config represents configuration options for the Tester
 type Config struct {
 	IncludeSubtests bool
-	Short           bool   // sets '-short' when running tests
-	Run             string // which tests should be run, if empty defaults to '.' (sets '-list' flag)
-	Seq             bool   // all tests should be run sequentially
+	Short           bool // sets '-short' when running tests
+	// Run selects the tests which are run, if empty defaults to '.'. As with 'go test -run' it may have '/' separated levels,
+	// e.g. 'TestParse/valid_', the first selects top-level tests and the rest the subtests which are run and narrowed
+	Run string
+	// Skip excludes tests and subtests matching it, as with 'go test -skip'. Tests with a '//gofindtests:skip' directive are never run
+	Skip    string
+	Seq     bool          // all tests should be run sequentially, overrides Serial and Groups
+	Timeout time.Duration // sets '-timeout' when running each test, if zero the default of the testing package is used. '//gofindtests:timeout <duration>' overrides it for a test
+	Tags    string        // comma separated build tags set when listing and compiling tests
+	// WorkspaceDependents additionally searches the tests of packages in other go.work modules which depend on the package
+	// only used by CoveredByPackages
+	WorkspaceDependents bool
+	Benchmarks          bool // also run each benchmark once to check if it covers the position
+	// Runs is the number of times each test is run, if greater than 1 tests which cover the position in only some runs
+	// are reported as unstable (see Result.Unstable), and UnstableBlocks can detect nondeterministic coverage
+	Runs int
+	// Isolate runs each test process with its own empty HOME, TMPDIR, and XDG base directories,
+	// so that tests which share them can run in parallel. The go build and module caches are preserved
+	Isolate bool
+	// CopyPackage runs each test process in its own copy of the package directory, including testdata,
+	// so that tests which write to fixed paths relative to the package can run in parallel
+	CopyPackage bool
+	Env         []string // additional 'KEY=value' environment variables of each test process
+	// Serial are regular expressions matching top-level tests which never run concurrently with any other test,
+	// as do tests with a '//gofindtests:serial' directive. All other tests run in parallel
+	Serial []string
+	// Groups maps the name of a group to a regular expression matching its top-level tests, tests of the same group
+	// never run concurrently with each other but may with any other test. '//gofindtests:group <name>' also adds a test to a group
+	Groups map[string]string
+	// KeepDir is a directory in which the test binary, cover profile, and test2json output of each test are kept,
+	// along with a manifest describing them (see Manifest). A relative directory is relative to the working directory.
+	// If empty they are written to a temporary directory which is removed
+	KeepDir string
+	// IncludeSetup counts the coverage of package initialization and TestMain towards every test.
+	// By default each test must cover the position more times than running the test binary without running any test
+	IncludeSetup bool
+	// IncludeFailing reports tests and subtests which fail after covering the position, with StatusFail,
+	// instead of returning a TestError for a failing test
+	IncludeFailing bool
+	// IncludeSkipped reports tests and subtests which are skipped after covering the position, with StatusSkip.
+	// By default skipped runs, e.g. tests skipped with Short after running setup code, don't cover the position
+	IncludeSkipped bool
+	// ExecWrapper runs each test binary using the program, as with 'go test -exec', e.g. 'sudo -E' or a sandbox script.
+	// It is split into fields which may be quoted, and invoked as 'xprog <binary> <args>' in the directory of the package
+	ExecWrapper string
 }
 
 // New constructs a new tester
@@ -39,29 +105,50 @@ func New(path string, line, col int, conf Config) (*Tester, error) {
 	if err := pos.setFilePkg(path); err != nil {
 		return nil, err
 	}
+	return newTester(pos, conf)
+}
 
-	var (
-		dir string
-		err error
-	)
-	if strings.HasPrefix(path, ".") {
-		rel, _ := filepath.Split(path)
-		dir, err = filepath.Abs(rel)
-	}
-	if err != nil {
-		return nil, err
+// NewPackage constructs a tester for all tests of a package, identified by its directory or import path
+// the returned tester has no position so is only useful for collecting profiles
+func NewPackage(path string, conf Config) (*Tester, error) {
+	pos := position{}
+	if err := pos.setPkg(path); err != nil {
+		return nil, &PackageNotFoundError{Path: path, Err: err}
 	}
+	return newTester(pos, conf)
+}
 
+func newTester(pos position, conf Config) (*Tester, error) {
 	runExp := "." // should default to running all
 	if conf.Run != "" {
 		runExp = conf.Run
 	}
+	if err := checkFilter(runExp, conf.Skip); err != nil {
+		return nil, err
+	}
+	execWrapper, err := splitQuoted(conf.ExecWrapper)
+	if err != nil {
+		return nil, fmt.Errorf("invalid exec wrapper: %w", err)
+	}
 
-	var finder coverFinder
+	var (
+		finder coverFinder
+		policy *runPolicy
+		keep   *keeper
+	)
+	if conf.KeepDir != "" {
+		if keep, err = newKeeper(conf.KeepDir); err != nil {
+			return nil, fmt.Errorf("invalid keep directory: %w", err)
+		}
+	}
 	if conf.Seq {
 		finder = sequentialFinder{}
 	} else {
-		finder = errGroupFinder{}
+		var err error
+		if policy, err = newRunPolicy(conf); err != nil {
+			return nil, err
+		}
+		finder = policyFinder{}
 	}
 
 	return &Tester{
@@ -69,36 +156,202 @@ func New(path string, line, col int, conf Config) (*Tester, error) {
 		includeSubtests: conf.IncludeSubtests,
 		short:           conf.Short,
 		run:             runExp,
-		dir:             dir,
+		skip:            conf.Skip,
+		timeout:         conf.Timeout,
+		dir:             pos.dir,
+		ws:              pos.ws.withTags(conf.Tags),
+		workspaceDeps:   conf.WorkspaceDependents,
+		benchmarks:      conf.Benchmarks,
+		runs:            conf.Runs,
+		isolate:         conf.Isolate,
+		copyPackage:     conf.CopyPackage,
+		env:             conf.Env,
+		policy:          policy,
+		keep:            keep,
+		includeSetup:    conf.IncludeSetup,
+		includeFailing:  conf.IncludeFailing,
+		includeSkipped:  conf.IncludeSkipped,
+		execWrapper:     execWrapper,
 		coverFinder:     finder,
 	}, nil
 }
 
+// runCount returns the number of times each test is run, which is always at least once
+func (t *Tester) runCount() int {
+	if t.runs < 1 {
+		return 1
+	}
+	return t.runs
+}
+
+// Package returns the import path of the package containing the provided position
+func (t *Tester) Package() string {
+	return t.testPos.pkg
+}
+
+// Dir returns the absolute directory of the package containing the provided position
+func (t *Tester) Dir() string {
+	return t.dir
+}
+
 // CoveredBy returns the tests which cover the provided position
 func (t *Tester) CoveredBy() ([]string, error) {
-	outputDir, err := ioutil.TempDir("", "test_finder")
+	results, err := t.coveringResults()
 	if err != nil {
 		return []string{}, err
 	}
-	defer os.RemoveAll(outputDir)
+	coveredBy := make([]string, len(results))
+	for i := range results {
+		coveredBy[i] = results[i].Name
+	}
+	return coveredBy, nil
+}
 
-	testBin, err := t.compileTest(outputDir)
+// Results returns the tests which cover the provided position along with details of each run
+// if WorkspaceDependents was set this includes tests of packages in other modules of the go.work workspace which depend on the package
+func (t *Tester) Results() ([]Result, error) {
+	results, err := t.linkedResults()
+	if err != nil {
+		return []Result{}, err
+	}
+	if !t.workspaceDeps {
+		return results, nil
+	}
+
+	dependents, err := t.ws.dependentPackages(t.testPos.pkg)
 	if err != nil {
-		return []string{}, fmt.Errorf("error compiling test for go pkg %s: %s", t.testPos.pkg, err)
+		return []Result{}, fmt.Errorf("error finding workspace packages depending on %s: %w", t.testPos.pkg, err)
 	}
+	for _, dep := range dependents {
+		depResults, err := t.forPackage(dep).linkedResults()
+		if err != nil {
+			return []Result{}, err
+		}
+		results = append(results, depResults...)
+	}
+	return results, nil
+}
 
-	allTests, err := findTests(t.testPos.pkg, t.run)
+// CoveredByPackages returns the tests which cover the provided position keyed by the import path of their package
+// if WorkspaceDependents was set this includes tests of packages in other modules of the go.work workspace which depend on the package
+func (t *Tester) CoveredByPackages() (map[string][]string, error) {
+	results, err := t.Results()
 	if err != nil {
-		return []string{}, fmt.Errorf("error finding tests in go pkg %s: %s", t.testPos.pkg, err)
+		return nil, err
 	}
+	byPackage := map[string][]string{t.testPos.pkg: {}}
+	for i := range results {
+		byPackage[results[i].Package] = append(byPackage[results[i].Package], results[i].Name)
+	}
+	return byPackage, nil
+}
 
-	if len(allTests) == 0 {
-		return []string{}, nil
+// linkedResults returns the results of the tests of the package with their positions and relationships set
+func (t *Tester) linkedResults() ([]Result, error) {
+	results, err := t.coveringResults()
+	if err != nil {
+		return []Result{}, err
+	}
+
+	positions, err := finder.PackageTests(t.dir)
+	if err != nil {
+		return []Result{}, fmt.Errorf("error finding tests in %s: %w", t.dir, err)
+	}
+	linkResults(results, t.testPos.pkg, positions)
+	return results, nil
+}
+
+// coveringResults runs the tests of the package and returns the results of those which cover the position
+func (t *Tester) coveringResults() ([]Result, error) {
+	outputDir, cleanup, err := t.outputDir()
+	if err != nil {
+		return []Result{}, err
+	}
+	defer cleanup()
+
+	testBin, allTests, err := t.suite(outputDir)
+	if err != nil || len(allTests) == 0 {
+		return []Result{}, err
+	}
+	if err := t.runBaseline(testBin, outputDir); err != nil {
+		return []Result{}, err
 	}
 
 	return t.coverFinder.coveringTests(t, testBin, outputDir, allTests, t.includeSubtests)
 }
 
+// Profiles runs each top-level test of the package and returns its cover profile
+// when including subtests each subtest is then also run on its own, and its profile follows those of the top-level tests
+func (t *Tester) Profiles() ([]TestProfile, error) {
+	outputDir, cleanup, err := t.outputDir()
+	if err != nil {
+		return []TestProfile{}, err
+	}
+	defer cleanup()
+
+	testBin, allTests, err := t.suite(outputDir)
+	if err != nil || len(allTests) == 0 {
+		return []TestProfile{}, err
+	}
+
+	profiles, err := t.coverFinder.profiles(t, testBin, outputDir, allTests)
+	if err != nil {
+		return []TestProfile{}, err
+	}
+	var subTests []string
+	for i := range profiles {
+		subTests = append(subTests, profiles[i].subtests...)
+	}
+	if len(subTests) != 0 {
+		subProfiles, err := t.coverFinder.profiles(t, testBin, outputDir, subTests)
+		if err != nil {
+			return []TestProfile{}, err
+		}
+		profiles = append(profiles, subProfiles...)
+	}
+	for i := range profiles {
+		profiles[i].Package = t.testPos.pkg
+	}
+	return profiles, nil
+}
+
+// suite compiles the test binary of the package into outputDir and lists the tests to run
+func (t *Tester) suite(outputDir string) (string, []string, error) {
+	testBin, err := t.compileTest(outputDir)
+	if err != nil {
+		return "", nil, &CompileError{Package: t.testPos.pkg, Err: err}
+	}
+	if t.keep != nil {
+		if err := t.keep.keepBinary(t.testPos.pkg, testBin); err != nil {
+			return "", nil, fmt.Errorf("error keeping test binary: %w", err)
+		}
+	}
+
+	allTests, err := findTests(t.ws, t.testPos.pkg, t.listPattern(), t.benchmarks)
+	if err != nil {
+		return "", nil, fmt.Errorf("error finding tests in go pkg %s: %w", t.testPos.pkg, err)
+	}
+	if err := t.prepareEnv(); err != nil {
+		return "", nil, fmt.Errorf("error resolving go environment: %w", err)
+	}
+	if err := t.loadDirectives(); err != nil {
+		return "", nil, fmt.Errorf("error parsing test directives: %w", err)
+	}
+	return testBin, t.withoutSkipped(allTests), nil
+}
+
+// forPackage returns a copy of the tester which runs the tests of the provided package
+// while still measuring the coverage of the original package
+func (t *Tester) forPackage(pkg listedPackage) *Tester {
+	depTester := *t
+	depTester.coverPkg = t.testPos.pkg
+	depTester.testPos.pkg = pkg.ImportPath
+	depTester.dir = pkg.Dir
+	depTester.ws.modRoot = findModRoot(pkg.Dir)
+	depTester.directives = nil
+	return &depTester
+}
+
 func (t *Tester) compileTest(outputDir string) (string, error) {
 	var binName strings.Builder
 	s := strings.Split(t.testPos.pkg, "/")
@@ -107,43 +360,120 @@ func (t *Tester) compileTest(outputDir string) (string, error) {
 
 	testBin := filepath.Join(outputDir, binName.String())
 
-	cmd := exec.Command("go", "test", t.testPos.pkg, "-cover", "-c", "-o", testBin)
-	err := cmd.Run()
-	if err != nil {
-		return "", parseCommandErr(err)
+	cmdArgs := []string{"test", t.testPos.pkg, "-covermode", "count", "-c", "-o", testBin}
+	if t.coverPkg != "" {
+		cmdArgs = append(cmdArgs, "-coverpkg", t.coverPkg)
+	}
+	cmd := t.ws.command(cmdArgs...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", parseCommandErr(cmd, err)
 	}
 	return testBin, nil
 }
 
-func (t *Tester) runCompiledTest(testName, testBin, outputDir string) (io.ReadCloser, io.Reader, error) {
-	var coverOut strings.Builder
-	coverOut.WriteString(strings.Replace(testName, "/", "", -1))
-	coverOut.WriteString(".out")
+// maxCoverFileName is the maximum length of the name of a cover output file before it is shortened with a hash
+const maxCoverFileName = 200
+
+// coverFileName returns the name of the cover output file of the test
+func coverFileName(testName string) string {
+	return escapeFileName(testName) + ".out"
+}
+
+// escapeFileName returns a file name without an extension which is unique to name
+// all characters other than letters, digits, '_', and '-' are escaped as '%XX' so distinct names never share a file
+func escapeFileName(s string) string {
+	var name strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '_' || c == '-' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9') {
+			name.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&name, "%%%02X", c)
+	}
+
+	escaped := name.String()
+	if len(escaped) > maxCoverFileName {
+		// most file systems limit names to 255 bytes
+		sum := sha256.Sum256([]byte(s))
+		escaped = escaped[:maxCoverFileName-33] + "-" + hex.EncodeToString(sum[:16])
+	}
+	return escaped
+}
 
-	pathToCover := filepath.Join(outputDir, coverOut.String())
+func (t *Tester) runCompiledTest(testName, testBin, outputDir string) (io.ReadCloser, *bytes.Buffer, error) {
+	pathToCover := filepath.Join(outputDir, coverFileName(testName))
+	var (
+		kept KeptTest
+		keep = t.keep != nil && testName != baselineTest // the baseline isn't a test, so isn't in the manifest
+	)
+	if keep {
+		run, name := t.keep.nextRun(t.testPos.pkg, testName)
+		kept = KeptTest{Test: testName, Run: run, Profile: name + ".out", Output: name + ".json"}
+		pathToCover = filepath.Join(outputDir, kept.Profile)
+	}
 
-	cmdArgs := []string{"tool", "test2json", testBin, "-test.run", testName, "-test.coverprofile", coverOut.String(), "-test.outputdir", outputDir}
-	if t.includeSubtests {
-		cmdArgs = append(cmdArgs, "-test.v")
+	cmdArgs := append([]string{"tool", "test2json"}, t.wrapped(testBin)...)
+	switch {
+	case testName == baselineTest:
+		cmdArgs = append(cmdArgs, "-test.run", "^$")
+	case kindOf(testName) == KindBenchmark:
+		cmdArgs = append(cmdArgs, "-test.run", "^$", "-test.bench", RunPattern([]string{testName}), "-test.benchtime", "1x")
+	default:
+		cmdArgs = append(cmdArgs, "-test.run", t.runPattern(testName))
 	}
+	if t.skip != "" && testName != baselineTest {
+		cmdArgs = append(cmdArgs, "-test.skip", t.skip)
+	}
+	// verbose output is needed to determine the status and elapsed time of each test
+	cmdArgs = append(cmdArgs, "-test.coverprofile", pathToCover, "-test.outputdir", outputDir, "-test.v")
 	if t.short {
 		cmdArgs = append(cmdArgs, "-test.short")
 	}
+	timeout := t.testTimeout(testName)
+	if timeout > 0 {
+		cmdArgs = append(cmdArgs, "-test.timeout", timeout.String())
+	}
 
-	cmd := exec.Command("go", cmdArgs...)
+	cmd := t.ws.command(cmdArgs...)
 
-	if t.dir != "" {
-		// run test in same dir as file to prevent issues due to dependency on file structure
-		cmd.Dir = t.dir
+	// run test in same dir as file to prevent issues due to dependency on file structure
+	cmd.Dir = t.dir
+	cleanup, err := t.isolateCmd(cmd, outputDir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error isolating test '%s': %w", testName, err)
 	}
+	defer cleanup()
 
-	var buf bytes.Buffer
+	var buf, stderr bytes.Buffer
 	cmd.Stdout = &buf
+	cmd.Stderr = &stderr
 
-	if err := cmd.Run(); err != nil {
-		return nil, nil, parseTestError(err, &buf)
+	runErr := cmd.Run()
+	if runErr != nil {
+		runErr = parseTestError(cmd, runErr, bytes.NewReader(buf.Bytes()), timeout)
+		if timeoutErr, ok := runErr.(*TimeoutError); ok && timeoutErr.Test == "" {
+			timeoutErr.Test = testName
+		}
+		kept.Error = runErr.Error()
+	}
+	if keep {
+		if err := t.keep.keepRun(t.testPos.pkg, kept, buf.Bytes(), outputDir); err != nil {
+			return nil, nil, fmt.Errorf("error keeping output of '%s': %w", testName, err)
+		}
+	}
+	if runErr != nil {
+		// failing tests still write their profile, which is used if they are included
+		if _, statErr := os.Stat(pathToCover); !t.includeFailing || !errors.Is(runErr, ErrTestFailed) || statErr != nil {
+			return nil, nil, runErr
+		}
 	}
 
 	coverProf, err := os.Open(pathToCover)
-	return coverProf, &buf, err
+	if err != nil {
+		return nil, nil, &ProfileError{Test: testName, Path: pathToCover, Err: err}
+	}
+	return coverProf, &buf, nil
 }
@@ -1,16 +1,20 @@
 package tester
 
 import (
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"sort"
+	"strings"
 	"testing"
+	"time"
 )
 
 var allFinders = map[string]func() coverFinder{
 	"sequential": func() coverFinder { return sequentialFinder{} },
 	"err_group":  func() coverFinder { return errGroupFinder{} },
+	"policy":     func() coverFinder { return policyFinder{} },
 }
 
 var coveredByTests = map[string]struct {
@@ -19,10 +23,12 @@ var coveredByTests = map[string]struct {
 	includeSubtests bool
 	short           bool
 	runExpr         string
+	timeout         time.Duration
 	line, col       int
 	expectCoveredBy []string
 	expectErr       bool
 	expectedErr     error
+	expectErrIs     error
 }{
 	"covered_by_1_of_4_tests": {
 		fileDir:  "size",
@@ -71,19 +77,29 @@ var coveredByTests = map[string]struct {
 		expectCoveredBy: []string{},
 	},
 	"invalid_path": {
-		fileDir:   "bad_path",
-		fileName:  "size.go",
-		expectErr: true,
+		fileDir:     "bad_path",
+		fileName:    "size.go",
+		expectErr:   true,
+		expectErrIs: ErrCompile,
 	},
 	"failing_test": {
 		fileDir:  "failing",
 		fileName: "fail.go",
 		line:     5, col: 0,
 		expectErr: true,
-		expectedErr: fmt.Errorf("error running test 'TestSum': %s", &testErr{
-			testName: "TestSum",
-			output:   "fail_test.go:12: Unexpected sum(1, 2) (expected = 3, actual = 2)",
+		expectedErr: fmt.Errorf("error running test 'TestSum': %s", &TestError{
+			Test:   "TestSum",
+			Output: "fail_test.go:12: Unexpected sum(1, 2) (expected = 3, actual = 2)",
 		}),
+		expectErrIs: ErrTestFailed,
+	},
+	"timed_out_test": {
+		fileDir:  "timeout",
+		fileName: "slow.go",
+		line:     8, col: 0,
+		timeout:     100 * time.Millisecond,
+		expectErr:   true,
+		expectErrIs: ErrTimeout,
 	},
 	"subtests_enabled_covered_by_subtests": {
 		fileDir:  "subtests",
@@ -107,6 +123,17 @@ var coveredByTests = map[string]struct {
 			"TestIsShort",
 		},
 	},
+	"test_names_are_prefixes": {
+		fileDir:  "exact",
+		fileName: "classify.go",
+		line:     6, col: 0, // negative case of classify()
+		includeSubtests: true,
+		expectCoveredBy: []string{
+			"TestClassifyCases",
+			"TestClassifyCases/f(-1)",
+			"TestClassifyNegative",
+		},
+	},
 	"subtests_enabled_no_subtests": {
 		fileDir:         "size",
 		fileName:        "size.go",
@@ -134,6 +161,7 @@ func TestCoveredBy(t *testing.T) {
 						includeSubtests: test.includeSubtests,
 						short:           test.short,
 						run:             test.runExpr,
+						timeout:         test.timeout,
 						coverFinder:     newFinder(),
 					}
 
@@ -152,6 +180,9 @@ func TestCoveredBy(t *testing.T) {
 								t.Errorf("Unexpected error message (expected = '%s', actual = '%s')", test.expectedErr, err)
 							}
 						}
+						if test.expectErrIs != nil && !errors.Is(err, test.expectErrIs) {
+							t.Errorf("Unexpected error kind (expected = '%s', actual = '%s')", test.expectErrIs, err)
+						}
 					} else {
 						if err != nil {
 							t.Errorf("Unexpected error checking for covering tests: %s", err)
@@ -179,7 +210,10 @@ func TestCoveredBy(t *testing.T) {
 	}
 }
 
-var coveringTests []string
+var (
+	coveringTests   []string
+	coveringResults []Result
+)
 
 var coveredByBenchmarks = map[string]struct {
 	fileDir         string
@@ -385,7 +419,7 @@ func BenchmarkCoveringTests(b *testing.B) {
 					os.RemoveAll(outputDir)
 					continue
 				}
-				allTests, err := findTests(tester.testPos.pkg, ".")
+				allTests, err := findTests(tester.ws, tester.testPos.pkg, ".", false)
 				if err != nil {
 					if !test.expectErr {
 						b.Errorf("Error finding tests: %s", err)
@@ -395,7 +429,7 @@ func BenchmarkCoveringTests(b *testing.B) {
 				}
 				b.Run(testName, func(b *testing.B) {
 					var (
-						covered []string
+						covered []Result
 						err     error
 					)
 					for n := 0; n < b.N; n++ {
@@ -406,7 +440,7 @@ func BenchmarkCoveringTests(b *testing.B) {
 							}
 						}
 					}
-					coveringTests = covered
+					coveringResults = covered
 				})
 
 				os.RemoveAll(outputDir)
@@ -414,3 +448,242 @@ func BenchmarkCoveringTests(b *testing.B) {
 		})
 	}
 }
+
+var coveredByPackagesTests = map[string]struct {
+	path                string
+	line, col           int
+	workspaceDependents bool
+	expectCoveredBy     map[string][]string
+}{
+	"nested_module": {
+		path: "../testdata/nested/double.go",
+		line: 5, col: 0, // body of double()
+		expectCoveredBy: map[string][]string{
+			"example.com/nested": {"TestDouble"},
+		},
+	},
+	"workspace_dependents_disabled": {
+		path: "../testdata/workspace/a/sign.go",
+		line: 8, col: 0, // negative case of Sign()
+		expectCoveredBy: map[string][]string{
+			"example.com/a": {},
+		},
+	},
+	"workspace_dependents_enabled": {
+		path: "../testdata/workspace/a/sign.go",
+		line: 8, col: 0, // negative case of Sign()
+		workspaceDependents: true,
+		expectCoveredBy: map[string][]string{
+			"example.com/a": {},
+			"example.com/b": {"TestAbsNegative"},
+		},
+	},
+}
+
+func TestCoveredByPackages(t *testing.T) {
+	for testName, test := range coveredByPackagesTests {
+		t.Run(testName, func(t *testing.T) {
+			tester, err := New(test.path, test.line, test.col, Config{WorkspaceDependents: test.workspaceDependents})
+			if err != nil {
+				t.Fatalf("Unexpected error constructing tester: %s", err)
+			}
+
+			coveredBy, err := tester.CoveredByPackages()
+			if err != nil {
+				t.Fatalf("Unexpected error checking for covering tests: %s", err)
+			}
+
+			if len(coveredBy) != len(test.expectCoveredBy) {
+				t.Fatalf("Unexpected CoveredByPackages (expected = %v, actual = %v)", test.expectCoveredBy, coveredBy)
+			}
+			for pkg, expectCoveredBy := range test.expectCoveredBy {
+				if fmt.Sprint(coveredBy[pkg]) != fmt.Sprint(expectCoveredBy) {
+					t.Errorf("Unexpected CoveredByPackages[%s] (expected = %v, actual = %v)", pkg, expectCoveredBy, coveredBy[pkg])
+				}
+			}
+		})
+	}
+}
+
+// expectedResult holds the fields of a Result which are stable between runs
+type expectedResult struct {
+	parent       string
+	children     []string
+	kind         Kind
+	positionLine int // 0 if no position is expected
+	blockLine    int
+}
+
+var resultsTests = map[string]struct {
+	path            string
+	line, col       int
+	includeSubtests bool
+	benchmarks      bool
+	expectedResults map[string]expectedResult
+}{
+	"subtests": {
+		path: "../testdata/subtests/len.go",
+		line: 9, col: 0, // "empty" case of length()
+		includeSubtests: true,
+		expectedResults: map[string]expectedResult{
+			"TestIsEmpty":             {children: []string{"TestIsEmpty/empty_input"}, kind: KindTest, positionLine: 23, blockLine: 9},
+			"TestIsEmpty/empty_input": {parent: "TestIsEmpty", kind: KindTest, blockLine: 9},
+			"TestIsShort":             {children: []string{"TestIsShort/empty_input"}, kind: KindTest, positionLine: 52, blockLine: 9},
+			"TestIsShort/empty_input": {parent: "TestIsShort", kind: KindTest, blockLine: 9},
+		},
+	},
+	"examples_and_fuzz_targets": {
+		path: "../testdata/kinds/reverse.go",
+		line: 7, col: 0, // body of loop in reverse()
+		expectedResults: map[string]expectedResult{
+			"Example_reverse": {kind: KindExample, positionLine: 14, blockLine: 7},
+			"FuzzReverse":     {kind: KindFuzz, positionLine: 25, blockLine: 7},
+		},
+	},
+	"benchmarks": {
+		path: "../testdata/kinds/reverse.go",
+		line: 7, col: 0, // body of loop in reverse()
+		benchmarks: true,
+		expectedResults: map[string]expectedResult{
+			"Example_reverse":  {kind: KindExample, positionLine: 14, blockLine: 7},
+			"BenchmarkReverse": {kind: KindBenchmark, positionLine: 19, blockLine: 7},
+			"FuzzReverse":      {kind: KindFuzz, positionLine: 25, blockLine: 7},
+		},
+	},
+}
+
+func TestResults(t *testing.T) {
+	for testName, test := range resultsTests {
+		t.Run(testName, func(t *testing.T) {
+			tester, err := New(test.path, test.line, test.col, Config{IncludeSubtests: test.includeSubtests, Benchmarks: test.benchmarks})
+			if err != nil {
+				t.Fatalf("Unexpected error constructing tester: %s", err)
+			}
+
+			results, err := tester.Results()
+			if err != nil {
+				t.Fatalf("Unexpected error checking for covering tests: %s", err)
+			}
+
+			if len(results) != len(test.expectedResults) {
+				t.Fatalf("Unexpected number of results (expected = %d, actual = %d): %v", len(test.expectedResults), len(results), results)
+			}
+			for _, result := range results {
+				expected, ok := test.expectedResults[result.Name]
+				if !ok {
+					t.Errorf("Unexpected result: %v", result)
+					continue
+				}
+				if result.Package != tester.Package() {
+					t.Errorf("Unexpected %s package (expected = %s, actual = %s)", result.Name, tester.Package(), result.Package)
+				}
+				if result.Parent != expected.parent {
+					t.Errorf("Unexpected %s parent (expected = '%s', actual = '%s')", result.Name, expected.parent, result.Parent)
+				}
+				if fmt.Sprint(result.Children) != fmt.Sprint(expected.children) {
+					t.Errorf("Unexpected %s children (expected = %v, actual = %v)", result.Name, expected.children, result.Children)
+				}
+				if result.Kind != expected.kind {
+					t.Errorf("Unexpected %s kind (expected = %s, actual = %s)", result.Name, expected.kind, result.Kind)
+				}
+				if expected.positionLine == 0 && result.Position != nil {
+					t.Errorf("Unexpected %s position: %v", result.Name, result.Position)
+				}
+				if expected.positionLine != 0 && (result.Position == nil || result.Position.Line != expected.positionLine) {
+					t.Errorf("Unexpected %s position (expected line = %d, actual = %v)", result.Name, expected.positionLine, result.Position)
+				}
+				if result.Status != StatusPass {
+					t.Errorf("Unexpected %s status (expected = %s, actual = %s)", result.Name, StatusPass, result.Status)
+				}
+				if result.Block.StartLine != expected.blockLine || result.Block.Count == 0 {
+					t.Errorf("Unexpected %s block (expected start line = %d, actual = %v)", result.Name, expected.blockLine, result.Block)
+				}
+			}
+		})
+	}
+}
+
+func TestProfiles(t *testing.T) {
+	tester, err := NewPackage("../testdata/kinds", Config{})
+	if err != nil {
+		t.Fatalf("Unexpected error constructing tester: %s", err)
+	}
+
+	profiles, err := tester.Profiles()
+	if err != nil {
+		t.Fatalf("Unexpected error collecting profiles: %s", err)
+	}
+
+	// TestReverseEmpty never enters the loop of reverse()
+	expectedCovers := map[string]bool{"TestReverseEmpty": false, "Example_reverse": true, "FuzzReverse": true}
+	if len(profiles) != len(expectedCovers) {
+		t.Fatalf("Unexpected number of profiles (expected = %d, actual = %d)", len(expectedCovers), len(profiles))
+	}
+	for _, prof := range profiles {
+		expectCovered, ok := expectedCovers[prof.Name]
+		if !ok {
+			t.Errorf("Unexpected profile: %s", prof.Name)
+			continue
+		}
+		if prof.Package != tester.Package() {
+			t.Errorf("Unexpected %s package (expected = %s, actual = %s)", prof.Name, tester.Package(), prof.Package)
+		}
+		if covered := prof.Profile.Covers("reverse.go", 7, 0); covered != expectCovered {
+			t.Errorf("Unexpected %s coverage of loop body (expected = %t, actual = %t)", prof.Name, expectCovered, covered)
+		}
+	}
+}
+
+func TestNewPackageNotFound(t *testing.T) {
+	_, err := NewPackage("../testdata/does_not_exist", Config{})
+	if !errors.Is(err, ErrPackageNotFound) {
+		t.Errorf("Unexpected error (expected = %s, actual = %v)", ErrPackageNotFound, err)
+	}
+}
+
+func TestCoverFileName(t *testing.T) {
+	var (
+		tests = []string{
+			"TestA/b",
+			"TestAb",
+			"TestA%2Fb",
+			"TestA/f(-1)",
+			"TestA/f_-1_",
+			strings.Repeat("TestLong/", 40) + "a",
+			strings.Repeat("TestLong/", 40) + "b",
+		}
+		names = make(map[string]string, len(tests))
+	)
+	for _, test := range tests {
+		name := coverFileName(test)
+		if other, ok := names[name]; ok {
+			t.Errorf("Tests '%s' and '%s' share cover file '%s'", other, test, name)
+		}
+		names[name] = test
+		if len(name) > maxCoverFileName+len(".out") {
+			t.Errorf("Cover file name of '%s' is too long (%d bytes)", test, len(name))
+		}
+		if strings.ContainsAny(name, "/\\") {
+			t.Errorf("Cover file name '%s' contains a path separator", name)
+		}
+	}
+}
+
+func TestTags(t *testing.T) {
+	for tags, expected := range map[string]string{
+		"":            "[TestUnit]",
+		"integration": "[TestIntegration TestUnit]",
+	} {
+		tester, err := New("../testdata/tags/tags.go", 4, 0, Config{Tags: tags})
+		if err != nil {
+			t.Fatalf("Unexpected error constructing tester: %s", err)
+		}
+		coveredBy, err := tester.CoveredBy()
+		if err != nil {
+			t.Fatalf("Unexpected error with tags '%s': %s", tags, err)
+		}
+		if fmt.Sprint(coveredBy) != expected {
+			t.Errorf("Unexpected covering tests with tags '%s' (expected = %s, actual = %v)", tags, expected, coveredBy)
+		}
+	}
+}
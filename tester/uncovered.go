@@ -0,0 +1,81 @@
+package tester
+
+import (
+	"sort"
+
+	"github.com/ShawnROGrady/go-find-tests/cover"
+	"github.com/ShawnROGrady/go-find-tests/finder"
+)
+
+// UncoveredFunc is a function with instrumented statements which no test executed
+type UncoveredFunc struct {
+	// Name of the function as finder.Func, empty for statements outside of any function,
+	// e.g. function literals in the initializers of package level variables
+	Name       string        `json:"name"`
+	File       string        `json:"file"`
+	StartLine  int           `json:"start_line,omitempty"`
+	EndLine    int           `json:"end_line,omitempty"`
+	Blocks     []cover.Block `json:"blocks"`     // uncovered blocks ordered by position
+	Uncovered  int           `json:"uncovered"`  // statements of Blocks
+	Statements int           `json:"statements"` // all instrumented statements of the function
+}
+
+// Uncovered groups the blocks of the matrix which no test executed by the function containing them
+// if relevant is not nil only the blocks it returns true for are reported, though every block counts towards Statements
+// functions are ordered by file and then position, those without any reported block are omitted
+func Uncovered(m Matrix, funcs []finder.Func, relevant func(cover.Block) bool) []UncoveredFunc {
+	var (
+		byFunc = make(map[int]*UncoveredFunc)    // keyed by the index of the function
+		outer  = make(map[string]*UncoveredFunc) // statements outside of any function, keyed by file
+		all    = []*UncoveredFunc{}
+	)
+	for _, b := range m.Blocks {
+		var u *UncoveredFunc
+		if i := containingFunc(funcs, b); i != -1 {
+			if u = byFunc[i]; u == nil {
+				fn := funcs[i]
+				u = &UncoveredFunc{Name: fn.Name, File: fn.File, StartLine: fn.StartLine, EndLine: fn.EndLine, Blocks: []cover.Block{}}
+				byFunc[i] = u
+				all = append(all, u)
+			}
+		} else if u = outer[b.File]; u == nil {
+			u = &UncoveredFunc{File: b.File, Blocks: []cover.Block{}}
+			outer[b.File] = u
+			all = append(all, u)
+		}
+
+		u.Statements += b.NumStmt
+		if b.Count != 0 || (relevant != nil && !relevant(b)) {
+			continue
+		}
+		u.Blocks = append(u.Blocks, b)
+		u.Uncovered += b.NumStmt
+	}
+
+	uncovered := []UncoveredFunc{}
+	for _, u := range all {
+		if len(u.Blocks) != 0 {
+			if u.StartLine == 0 {
+				u.StartLine, u.EndLine = u.Blocks[0].StartLine, u.Blocks[len(u.Blocks)-1].EndLine
+			}
+			uncovered = append(uncovered, *u)
+		}
+	}
+	sort.SliceStable(uncovered, func(i, j int) bool {
+		if uncovered[i].File != uncovered[j].File {
+			return uncovered[i].File < uncovered[j].File
+		}
+		return uncovered[i].StartLine < uncovered[j].StartLine
+	})
+	return uncovered
+}
+
+// containingFunc returns the index of the function whose declaration contains the block, or -1 if there is none
+func containingFunc(funcs []finder.Func, b cover.Block) int {
+	for i := range funcs {
+		if funcs[i].File == b.File && funcs[i].StartLine <= b.StartLine && b.EndLine <= funcs[i].EndLine {
+			return i
+		}
+	}
+	return -1
+}
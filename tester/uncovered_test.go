@@ -0,0 +1,63 @@
+package tester
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ShawnROGrady/go-find-tests/cover"
+	"github.com/ShawnROGrady/go-find-tests/finder"
+)
+
+var uncoveredTests = map[string]struct {
+	relevant        func(cover.Block) bool
+	expectUncovered string
+}{
+	"all": {
+		expectUncovered: "[a.go:1-1 :[a.go:1.10,1.20] 1/1 a.go:3-9 Sum:[a.go:5.1,6.1 a.go:8.1,9.1] 5/8 b.go:2-4 (*T).Inc:[b.go:3.1,4.1] 1/1]",
+	},
+	"relevant_lines": {
+		relevant:        func(b cover.Block) bool { return b.File == "a.go" && b.StartLine <= 8 && b.EndLine >= 8 },
+		expectUncovered: "[a.go:3-9 Sum:[a.go:8.1,9.1] 3/8]",
+	},
+	"none_relevant": {
+		relevant:        func(cover.Block) bool { return false },
+		expectUncovered: "[]",
+	},
+}
+
+func TestUncovered(t *testing.T) {
+	prof, err := cover.New(strings.NewReader("mode: count\n" +
+		"pkg/a.go:1.10,1.20 1 0\n" + // package level function literal
+		"pkg/a.go:3.1,4.1 1 2\n" +
+		"pkg/a.go:5.1,6.1 2 0\n" +
+		"pkg/a.go:7.1,7.10 2 1\n" +
+		"pkg/a.go:8.1,9.1 3 0\n" +
+		"pkg/b.go:3.1,4.1 1 0\n" +
+		"pkg/b.go:6.1,7.1 1 1"))
+	if err != nil {
+		t.Fatalf("Unexpected error parsing profile: %s", err)
+	}
+	m := NewMatrix([]TestProfile{{Package: "example.com/pkg", Name: "TestA", Profile: prof}})
+	funcs := []finder.Func{
+		{Name: "Sum", File: "a.go", StartLine: 3, EndLine: 9},
+		{Name: "(*T).Inc", File: "b.go", StartLine: 2, EndLine: 4},
+		{Name: "(*T).Dec", File: "b.go", StartLine: 5, EndLine: 8},
+	}
+
+	for testName, test := range uncoveredTests {
+		t.Run(testName, func(t *testing.T) {
+			actual := []string{}
+			for _, u := range Uncovered(m, funcs, test.relevant) {
+				blocks := []string{}
+				for _, b := range u.Blocks {
+					blocks = append(blocks, fmt.Sprintf("%s:%d.%d,%d.%d", b.File, b.StartLine, b.StartCol, b.EndLine, b.EndCol))
+				}
+				actual = append(actual, fmt.Sprintf("%s:%d-%d %s:%v %d/%d", u.File, u.StartLine, u.EndLine, u.Name, blocks, u.Uncovered, u.Statements))
+			}
+			if fmt.Sprint(actual) != test.expectUncovered {
+				t.Errorf("Unexpected uncovered functions (expected = %s, actual = %v)", test.expectUncovered, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package tester
+
+import (
+	"sort"
+
+	"github.com/ShawnROGrady/go-find-tests/cover"
+)
+
+// UnstableBlock is a block which a test covered in only some of its runs
+type UnstableBlock struct {
+	Block       cover.Block `json:"block"` // Count is the total over the runs of the test
+	Test        string      `json:"test"`
+	Runs        int         `json:"runs"`
+	CoveredRuns int         `json:"covered_runs"`
+}
+
+// UnstableBlocks runs each top-level test of the package the configured number of times
+// and returns the blocks which each test covered in some, but not all, of its runs
+// blocks are ordered by file and position, and then by test
+func (t *Tester) UnstableBlocks() ([]UnstableBlock, error) {
+	outputDir, cleanup, err := t.outputDir()
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	testBin, allTests, err := t.suite(outputDir)
+	if err != nil || len(allTests) == 0 {
+		return []UnstableBlock{}, err
+	}
+
+	type testBlock struct {
+		test string
+		key  cover.BlockKey
+	}
+	covered := make(map[testBlock]*UnstableBlock)
+	for run := 0; run < t.runCount(); run++ {
+		profiles, err := t.coverFinder.profiles(t, testBin, outputDir, allTests)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range profiles {
+			for _, b := range p.Profile.Blocks() {
+				if b.Count == 0 {
+					continue
+				}
+				tb := testBlock{test: p.Name, key: b.Key()}
+				if covered[tb] == nil {
+					covered[tb] = &UnstableBlock{Block: b, Test: p.Name, Runs: t.runCount()}
+					covered[tb].Block.Count = 0
+				}
+				covered[tb].CoveredRuns++
+				covered[tb].Block.Count += b.Count
+			}
+		}
+	}
+
+	unstable := []UnstableBlock{}
+	for _, u := range covered {
+		if u.CoveredRuns < u.Runs {
+			unstable = append(unstable, *u)
+		}
+	}
+	sort.Slice(unstable, func(i, j int) bool {
+		if unstable[i].Block.Key() != unstable[j].Block.Key() {
+			return unstable[i].Block.Before(unstable[j].Block)
+		}
+		return unstable[i].Test < unstable[j].Test
+	})
+	return unstable, nil
+}
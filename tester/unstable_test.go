@@ -0,0 +1,96 @@
+package tester
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// setFlakyCounter points the flaky fixture at a fresh run counter, returning a function which removes it
+func setFlakyCounter(t *testing.T) func() {
+	t.Helper()
+	dir, err := ioutil.TempDir("", "flaky")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	os.Setenv("FLAKY_COUNTER", filepath.Join(dir, "count"))
+	return func() {
+		os.Unsetenv("FLAKY_COUNTER")
+		os.RemoveAll(dir)
+	}
+}
+
+var runsTests = map[string]struct {
+	line          int
+	expectedRuns  string
+	expectedFlaky []string
+}{
+	"covered_by_every_run_of_one_test": {
+		line:          6,
+		expectedRuns:  "[TestAlternating:2/4 TestEven:4/4]",
+		expectedFlaky: []string{"TestAlternating"},
+	},
+	"covered_by_some_runs": {
+		line:          8,
+		expectedRuns:  "[TestAlternating:2/4]",
+		expectedFlaky: []string{"TestAlternating"},
+	},
+	"covered_by_every_run": {
+		line:         5,
+		expectedRuns: "[TestAlternating:4/4 TestEven:4/4]",
+	},
+}
+
+func TestResultsRuns(t *testing.T) {
+	for testName, test := range runsTests {
+		test := test
+		t.Run(testName, func(t *testing.T) {
+			defer setFlakyCounter(t)()
+			tester, err := New("../testdata/flaky/parity.go", test.line, 0, Config{Runs: 4})
+			if err != nil {
+				t.Fatalf("Unexpected error constructing tester: %s", err)
+			}
+			results, err := tester.Results()
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+
+			runs, flaky := []string{}, []string{}
+			for _, r := range results {
+				runs = append(runs, fmt.Sprintf("%s:%d/%d", r.Name, r.CoveredRuns, r.Runs))
+				if r.Unstable() {
+					flaky = append(flaky, r.Name)
+				}
+			}
+			if fmt.Sprint(runs) != test.expectedRuns {
+				t.Errorf("Unexpected runs (expected = %s, actual = %v)", test.expectedRuns, runs)
+			}
+			if fmt.Sprint(flaky) != fmt.Sprint(test.expectedFlaky) {
+				t.Errorf("Unexpected unstable tests (expected = %v, actual = %v)", test.expectedFlaky, flaky)
+			}
+		})
+	}
+}
+
+func TestUnstableBlocks(t *testing.T) {
+	defer setFlakyCounter(t)()
+	tester, err := NewPackage("../testdata/flaky", Config{Runs: 4})
+	if err != nil {
+		t.Fatalf("Unexpected error constructing tester: %s", err)
+	}
+	unstable, err := tester.UnstableBlocks()
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+
+	actual := []string{}
+	for _, u := range unstable {
+		actual = append(actual, fmt.Sprintf("%s:%d %s %d/%d", u.Block.File, u.Block.StartLine, u.Test, u.CoveredRuns, u.Runs))
+	}
+	expected := "[parity.go:6 TestAlternating 2/4 parity.go:8 TestAlternating 2/4]"
+	if fmt.Sprint(actual) != expected {
+		t.Errorf("Unexpected unstable blocks (expected = %s, actual = %v)", expected, actual)
+	}
+}
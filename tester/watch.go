@@ -0,0 +1,127 @@
+package tester
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ShawnROGrady/go-find-tests/finder"
+)
+
+// Watch polls the source of the package and re-determines the covering tests each time it changes, until ctx is done
+// onChange is called with the results of the initial run and of each run after a change, or with the error of the run
+//
+// the test binary is always recompiled, but only tests whose declaration changed are rerun
+// unless the change could affect every test of the package (e.g. a change to a helper or non-test file)
+// only the directory of the package is watched, WorkspaceDependents is ignored
+func (t *Tester) Watch(ctx context.Context, interval time.Duration, onChange func([]Result, error)) error {
+	var (
+		ticker    = time.NewTicker(interval)
+		cached    map[string][]Result // unlinked results of each top-level test, nil until the first successful run
+		succeeded finder.SourceHashes // hashes of the source as of the last successful run
+		attempted finder.SourceHashes // hashes of the source as of the last run
+		started   bool
+		hashErr   bool // whether the source could not be read when last polled
+	)
+	defer ticker.Stop()
+
+	for {
+		hashes, err := finder.PackageHashes(t.dir)
+		switch {
+		case err != nil:
+			// most likely a file is mid-edit, only report the error once
+			if !hashErr {
+				onChange(nil, fmt.Errorf("error reading source of %s: %w", t.testPos.pkg, err))
+			}
+			hashErr = true
+		case !started || hashErr || !hashes.Equal(attempted):
+			started, hashErr, attempted = true, false, hashes
+
+			var affected map[string]bool
+			if cached != nil && hashes.Shared == succeeded.Shared {
+				affected = changedTests(succeeded, hashes)
+			}
+			results, runCache, err := t.watchRun(cached, affected)
+			if err == nil {
+				cached, succeeded = runCache, hashes
+			}
+			onChange(results, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// changedTests returns the tests which were added or whose declaration changed
+func changedTests(before, after finder.SourceHashes) map[string]bool {
+	changed := make(map[string]bool)
+	for test, hash := range after.Tests {
+		if before.Tests[test] != hash {
+			changed[test] = true
+		}
+	}
+	return changed
+}
+
+// watchRun recompiles the package and runs the top-level tests which are affected or not yet cached
+// if affected is nil all tests are run
+// the linked results are returned along with the updated cache of unlinked results
+func (t *Tester) watchRun(cached map[string][]Result, affected map[string]bool) ([]Result, map[string][]Result, error) {
+	outputDir, cleanup, err := t.outputDir()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer cleanup()
+
+	testBin, allTests, err := t.suite(outputDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	toRun := []string{}
+	for _, test := range allTests {
+		if _, ok := cached[test]; !ok || affected == nil || affected[test] {
+			toRun = append(toRun, test)
+		}
+	}
+
+	runResults := []Result{}
+	if len(toRun) != 0 {
+		if err := t.runBaseline(testBin, outputDir); err != nil {
+			return nil, nil, err
+		}
+		runResults, err = t.coverFinder.coveringTests(t, testBin, outputDir, toRun, t.includeSubtests)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	runCache := make(map[string][]Result, len(allTests))
+	for _, test := range toRun {
+		runCache[test] = []Result{}
+	}
+	for i := range runResults {
+		top := strings.SplitN(runResults[i].Name, "/", 2)[0]
+		runCache[top] = append(runCache[top], runResults[i])
+	}
+
+	results := []Result{}
+	for _, test := range allTests {
+		if _, ok := runCache[test]; !ok {
+			runCache[test] = cached[test]
+		}
+		results = append(results, runCache[test]...)
+	}
+
+	positions, err := finder.PackageTests(t.dir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error finding tests in %s: %w", t.dir, err)
+	}
+	linkResults(results, t.testPos.pkg, positions)
+	return results, runCache, nil
+}
@@ -0,0 +1,158 @@
+package tester
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	watchSource = `package watch
+
+func sign(n int) int {
+	if n < 0 {
+		return -1
+	}
+	return 1
+}
+`
+	// each test records that it ran so that reruns can be checked
+	watchTestSource = `package watch
+
+import (
+	"os"
+	"testing"
+)
+
+func record(t *testing.T) {
+	f, err := os.OpenFile("runs.log", os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	f.WriteString(t.Name() + "\n")
+}
+
+func TestPositive(t *testing.T) {
+	record(t)
+	sign(1)
+}
+
+func TestZero(t *testing.T) {
+	record(t)
+	sign(0)
+}
+`
+)
+
+type watchUpdate struct {
+	results []Result
+	err     error
+}
+
+func TestWatch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "watch")
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	write := func(name, src string) {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(src), 0644); err != nil {
+			t.Fatalf("Unexpected error writing %s: %s", name, err)
+		}
+	}
+	// returns the tests which ran since the last call
+	runs := func() string {
+		b, err := ioutil.ReadFile(filepath.Join(dir, "runs.log"))
+		if err != nil {
+			t.Fatalf("Unexpected error reading runs: %s", err)
+		}
+		if err := os.Remove(filepath.Join(dir, "runs.log")); err != nil {
+			t.Fatalf("Unexpected error removing runs: %s", err)
+		}
+		return strings.Join(strings.Fields(string(b)), ",")
+	}
+	write("go.mod", "module example.com/watch\n")
+	write("watch.go", watchSource)
+	write("watch_test.go", watchTestSource)
+
+	tester, err := New(filepath.Join(dir, "watch.go"), 5, 0, Config{Seq: true}) // negative case of sign()
+	if err != nil {
+		t.Fatalf("Unexpected error constructing tester: %s", err)
+	}
+
+	var (
+		ctx, cancel = context.WithCancel(context.Background())
+		updates     = make(chan watchUpdate)
+		done        = make(chan error)
+	)
+	defer cancel()
+	go func() {
+		done <- tester.Watch(ctx, 10*time.Millisecond, func(results []Result, err error) {
+			updates <- watchUpdate{results: results, err: err}
+		})
+	}()
+
+	var steps = []struct {
+		name             string
+		change           func()
+		expectedCoversBy string
+		expectedRuns     string
+	}{
+		{
+			name:             "initial",
+			change:           func() {},
+			expectedCoversBy: "",
+			expectedRuns:     "TestPositive,TestZero",
+		},
+		{
+			name:             "test_changed",
+			change:           func() { write("watch_test.go", strings.Replace(watchTestSource, "sign(0)", "sign(-1)", 1)) },
+			expectedCoversBy: "TestZero",
+			expectedRuns:     "TestZero",
+		},
+		{
+			name:             "source_changed",
+			change:           func() { write("watch.go", strings.Replace(watchSource, "n < 0", "n <= 0", 1)) },
+			expectedCoversBy: "TestZero",
+			expectedRuns:     "TestPositive,TestZero",
+		},
+	}
+
+	for _, step := range steps {
+		step.change()
+		select {
+		case update := <-updates:
+			if update.err != nil {
+				t.Fatalf("%s: unexpected error: %s", step.name, update.err)
+			}
+			names := make([]string, len(update.results))
+			for i := range update.results {
+				names[i] = update.results[i].Name
+			}
+			if coveredBy := strings.Join(names, ","); coveredBy != step.expectedCoversBy {
+				t.Errorf("%s: unexpected covering tests (expected = '%s', actual = '%s')", step.name, step.expectedCoversBy, coveredBy)
+			}
+			if ran := runs(); ran != step.expectedRuns {
+				t.Errorf("%s: unexpected tests run (expected = '%s', actual = '%s')", step.name, step.expectedRuns, ran)
+			}
+		case <-time.After(time.Minute):
+			t.Fatalf("%s: timed out waiting for results", step.name)
+		}
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Unexpected error from watch: %s", err)
+		}
+	case update := <-updates:
+		t.Errorf("Unexpected update after cancel: %v", update)
+	}
+}
@@ -0,0 +1,210 @@
+package tester
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// workspace describes the module, and optionally the go.work workspace, which owns a package
+// all go commands for the package are run from its root with the environment it requires
+type workspace struct {
+	modRoot  string // directory containing the go.mod of the owning module, empty if not in module mode
+	workFile string // path to the go.work file, empty if not in workspace mode
+	goflags  string
+}
+
+// findWorkspace locates the module and workspace which own the provided directory
+func findWorkspace(dir string) (workspace, error) {
+	modRoot := findModRoot(dir)
+	if modRoot == "" {
+		return workspace{}, nil
+	}
+
+	ws := workspace{modRoot: modRoot}
+	cmd := exec.Command("go", "env", "GOWORK", "GOFLAGS")
+	cmd.Dir = modRoot
+	output, err := cmd.Output()
+	if err != nil {
+		return workspace{}, parseCommandErr(cmd, err)
+	}
+	lines := strings.Split(strings.TrimRight(string(output), "\n"), "\n")
+	if len(lines) > 1 {
+		ws.goflags = lines[1]
+	}
+
+	if workFile := lines[0]; workFile != "" && workFile != "off" {
+		uses, err := workspaceModules(modRoot, workFile)
+		if err != nil {
+			return workspace{}, err
+		}
+		for i := range uses {
+			if uses[i] == modRoot {
+				ws.workFile = workFile
+				break
+			}
+		}
+	}
+	ws.goflags = sanitizeGoflags(ws.goflags, ws.workFile != "")
+
+	return ws, nil
+}
+
+// findModRoot returns the nearest directory at or above dir which contains a go.mod file
+func findModRoot(dir string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	for {
+		if info, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil && !info.IsDir() {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// workspaceModules returns the absolute directories of all modules used by the go.work file
+func workspaceModules(workDir, workFile string) ([]string, error) {
+	cmd := exec.Command("go", "work", "edit", "-json", workFile)
+	cmd.Dir = workDir
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, parseCommandErr(cmd, err)
+	}
+
+	var work struct {
+		Use []struct {
+			DiskPath string
+		}
+	}
+	if err := json.Unmarshal(output, &work); err != nil {
+		return nil, err
+	}
+
+	modules := make([]string, len(work.Use))
+	for i := range work.Use {
+		dir := work.Use[i].DiskPath
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(filepath.Dir(workFile), dir)
+		}
+		modules[i] = filepath.Clean(dir)
+	}
+	return modules, nil
+}
+
+// withTags returns the workspace with the build tags added to the GOFLAGS of every go command
+// tags may be comma or space separated
+func (w workspace) withTags(tags string) workspace {
+	fields := strings.Fields(strings.Replace(tags, ",", " ", -1))
+	if len(fields) == 0 {
+		return w
+	}
+	w.goflags = strings.TrimSpace(w.goflags + " -tags=" + strings.Join(fields, ","))
+	return w
+}
+
+// sanitizeGoflags removes flags which are invalid in workspace mode
+// see: 'go help work'
+func sanitizeGoflags(goflags string, inWorkspace bool) string {
+	if !inWorkspace {
+		return goflags
+	}
+	var flags []string
+	for _, f := range strings.Fields(goflags) {
+		if strings.HasPrefix(f, "-mod=") && f != "-mod=readonly" && f != "-mod=vendor" {
+			continue
+		}
+		if strings.HasPrefix(f, "-modfile=") {
+			continue
+		}
+		flags = append(flags, f)
+	}
+	return strings.Join(flags, " ")
+}
+
+// env returns the environment go commands should be run with
+func (w workspace) env() []string {
+	if w.modRoot == "" {
+		return nil
+	}
+	gowork := "off"
+	if w.workFile != "" {
+		gowork = w.workFile
+	}
+	return append(os.Environ(), "GOWORK="+gowork, "GOFLAGS="+w.goflags)
+}
+
+// goEnv returns the resolved value of each go environment variable as 'NAME=value'
+func (w workspace) goEnv(names ...string) ([]string, error) {
+	cmd := w.command(append([]string{"env"}, names...)...)
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, parseCommandErr(cmd, err)
+	}
+	values := strings.Split(strings.TrimRight(string(output), "\n"), "\n")
+	env := make([]string, 0, len(names))
+	for i, name := range names {
+		if i < len(values) && values[i] != "" {
+			env = append(env, name+"="+values[i])
+		}
+	}
+	return env, nil
+}
+
+// command constructs a go command run from the root of the module
+func (w workspace) command(args ...string) *exec.Cmd {
+	cmd := exec.Command("go", args...)
+	cmd.Dir = w.modRoot
+	cmd.Env = w.env()
+	return cmd
+}
+
+// dependentPackages returns the packages of the other workspace modules whose tests depend on pkg
+func (w workspace) dependentPackages(pkg string) ([]listedPackage, error) {
+	if w.workFile == "" {
+		return nil, nil
+	}
+	modules, err := workspaceModules(w.modRoot, w.workFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		dependents = []listedPackage{}
+		seen       = make(map[string]bool)
+	)
+	for _, modDir := range modules {
+		if modDir == w.modRoot {
+			continue
+		}
+		cmd := exec.Command("go", "list", "-test", "-f", `{{if .ForTest}}{{.ForTest}}{{"\t"}}{{.Dir}}{{"\t"}}{{join .Deps " "}}{{end}}`, "./...")
+		cmd.Dir = modDir
+		cmd.Env = w.env()
+		output, err := cmd.Output()
+		if err != nil {
+			return nil, parseCommandErr(cmd, err)
+		}
+
+		for _, line := range strings.Split(string(output), "\n") {
+			fields := strings.SplitN(line, "\t", 3)
+			if len(fields) != 3 || seen[fields[0]] {
+				continue
+			}
+			for _, dep := range strings.Fields(fields[2]) {
+				if dep == pkg {
+					seen[fields[0]] = true
+					dependents = append(dependents, listedPackage{ImportPath: fields[0], Dir: fields[1]})
+					break
+				}
+			}
+		}
+	}
+	return dependents, nil
+}
@@ -0,0 +1,72 @@
+package tester
+
+import "testing"
+
+var sanitizeGoflagsTests = map[string]struct {
+	goflags         string
+	inWorkspace     bool
+	expectedGoflags string
+}{
+	"module_mode_unchanged": {
+		goflags:         "-mod=mod -tags=integration",
+		inWorkspace:     false,
+		expectedGoflags: "-mod=mod -tags=integration",
+	},
+	"workspace_mode_mod_removed": {
+		goflags:         "-mod=mod -tags=integration",
+		inWorkspace:     true,
+		expectedGoflags: "-tags=integration",
+	},
+	"workspace_mode_readonly_kept": {
+		goflags:         "-mod=readonly",
+		inWorkspace:     true,
+		expectedGoflags: "-mod=readonly",
+	},
+	"workspace_mode_modfile_removed": {
+		goflags:         "-modfile=go.test.mod -race",
+		inWorkspace:     true,
+		expectedGoflags: "-race",
+	},
+}
+
+func TestSanitizeGoflags(t *testing.T) {
+	for testName, test := range sanitizeGoflagsTests {
+		t.Run(testName, func(t *testing.T) {
+			goflags := sanitizeGoflags(test.goflags, test.inWorkspace)
+			if goflags != test.expectedGoflags {
+				t.Errorf("Unexpected goflags (expected = '%s', actual = '%s')", test.expectedGoflags, goflags)
+			}
+		})
+	}
+}
+
+var withTagsTests = map[string]struct {
+	goflags         string
+	tags            string
+	expectedGoflags string
+}{
+	"no_tags": {
+		goflags:         "-mod=mod",
+		expectedGoflags: "-mod=mod",
+	},
+	"comma_separated": {
+		tags:            "integration,e2e",
+		expectedGoflags: "-tags=integration,e2e",
+	},
+	"space_separated": {
+		goflags:         "-mod=mod",
+		tags:            " integration  e2e",
+		expectedGoflags: "-mod=mod -tags=integration,e2e",
+	},
+}
+
+func TestWithTags(t *testing.T) {
+	for testName, test := range withTagsTests {
+		t.Run(testName, func(t *testing.T) {
+			ws := workspace{goflags: test.goflags}.withTags(test.tags)
+			if ws.goflags != test.expectedGoflags {
+				t.Errorf("Unexpected goflags (expected = '%s', actual = '%s')", test.expectedGoflags, ws.goflags)
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package tester
+
+import (
+	"fmt"
+	"strings"
+)
+
+// splitQuoted splits s into fields separated by spaces, a field may be quoted with single or double quotes to contain spaces
+// this follows how the go command splits '-exec' and other flags which name a program
+func splitQuoted(s string) ([]string, error) {
+	var (
+		fields []string
+		field  strings.Builder
+		quote  byte // the opening quote of the current field, if any
+		inside bool // whether a field has been started, which may be an empty quoted field
+	)
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+				continue
+			}
+			field.WriteByte(c)
+		case c == '\'' || c == '"':
+			quote, inside = c, true
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			if inside {
+				fields = append(fields, field.String())
+				field.Reset()
+				inside = false
+			}
+		default:
+			field.WriteByte(c)
+			inside = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated %c quote in '%s'", quote, s)
+	}
+	if inside {
+		fields = append(fields, field.String())
+	}
+	return fields, nil
+}
+
+// wrapped returns the command which runs the test binary, followed by its arguments,
+// using the exec wrapper if set, as 'go test -exec' would: 'xprog <binary> <args>'
+func (t *Tester) wrapped(testBin string) []string {
+	cmd := make([]string, 0, len(t.execWrapper)+1)
+	cmd = append(cmd, t.execWrapper...)
+	return append(cmd, testBin)
+}
+
+// execWrapperFlag returns the exec wrapper as the value of 'go test -exec', quoting fields which contain spaces or quotes
+func (t *Tester) execWrapperFlag() string {
+	fields := make([]string, len(t.execWrapper))
+	for i, field := range t.execWrapper {
+		switch {
+		case field != "" && !strings.ContainsAny(field, " \t\n\r'\""):
+			fields[i] = field
+		case strings.Contains(field, "'"):
+			fields[i] = `"` + field + `"`
+		default:
+			fields[i] = "'" + field + "'"
+		}
+	}
+	return strings.Join(fields, " ")
+}
@@ -0,0 +1,122 @@
+package tester
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+var splitQuotedTests = map[string]struct {
+	expectedFields []string
+	expectErr      bool
+}{
+	"":                       {expectedFields: []string{}},
+	"sudo -E":                {expectedFields: []string{"sudo", "-E"}},
+	"  taskset  -c 0 ":       {expectedFields: []string{"taskset", "-c", "0"}},
+	`'./my wrapper' "a b" c`: {expectedFields: []string{"./my wrapper", "a b", "c"}},
+	`wrap "it's" ''`:         {expectedFields: []string{"wrap", "it's", ""}},
+	`wrap a"b c"d`:           {expectedFields: []string{"wrap", "ab cd"}},
+	`wrap "unterminated`:     {expectErr: true},
+}
+
+func TestSplitQuoted(t *testing.T) {
+	for s, test := range splitQuotedTests {
+		t.Run(s, func(t *testing.T) {
+			fields, err := splitQuoted(s)
+			if test.expectErr {
+				if err == nil {
+					t.Error("Unexpectedly no error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if fmt.Sprintf("%q", fields) != fmt.Sprintf("%q", test.expectedFields) {
+				t.Errorf("Unexpected fields (expected = %q, actual = %q)", test.expectedFields, fields)
+			}
+
+			// the flag passed to 'go test -exec' must split into the same fields
+			flagFields, err := splitQuoted((&Tester{execWrapper: fields}).execWrapperFlag())
+			if err != nil {
+				t.Fatalf("Unexpected error splitting flag: %s", err)
+			}
+			if fmt.Sprintf("%q", flagFields) != fmt.Sprintf("%q", test.expectedFields) {
+				t.Errorf("Unexpected fields of flag (expected = %q, actual = %q)", test.expectedFields, flagFields)
+			}
+		})
+	}
+}
+
+var execWrapperTests = map[string]struct {
+	execWrapper     string
+	line            int
+	expectErr       bool
+	expectCoveredBy []string
+}{
+	"unwrapped": {
+		line:            7,
+		expectCoveredBy: []string{},
+	},
+	"wrapped": {
+		execWrapper:     "./wrap.sh yes",
+		line:            7,
+		expectCoveredBy: []string{"TestWrappedBy"},
+	},
+	"wrapped_with_quoted_argument": {
+		execWrapper:     `'./wrap.sh' "two words"`,
+		line:            7,
+		expectCoveredBy: []string{"TestWrappedBy"},
+	},
+	"wrapped_doesnt_cover_unwrapped": {
+		execWrapper:     "./wrap.sh yes",
+		line:            9,
+		expectCoveredBy: []string{},
+	},
+	"invalid": {
+		execWrapper: `./wrap.sh "yes`,
+		line:        7,
+		expectErr:   true,
+	},
+}
+
+func TestExecWrapper(t *testing.T) {
+	for testName, test := range execWrapperTests {
+		test := test
+		t.Run(testName, func(t *testing.T) {
+			tester, err := New("../testdata/wrapper/wrapper.go", test.line, 0, Config{ExecWrapper: test.execWrapper})
+			if test.expectErr {
+				if err == nil {
+					t.Error("Unexpectedly no error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error constructing tester: %s", err)
+			}
+			coveredBy, err := tester.CoveredBy()
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if fmt.Sprint(coveredBy) != fmt.Sprint(test.expectCoveredBy) {
+				t.Errorf("Unexpected CoveredBy (expected = %v, actual = %v)", test.expectCoveredBy, coveredBy)
+			}
+		})
+	}
+}
+
+func TestExecWithWrapper(t *testing.T) {
+	tester, err := New("../testdata/wrapper/wrapper.go", 7, 0, Config{ExecWrapper: `./wrap.sh "two words"`})
+	if err != nil {
+		t.Fatalf("Unexpected error constructing tester: %s", err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	if err := tester.Exec([]Result{{Name: "TestWrappedBy", Kind: KindTest}}, &stdout, &stderr, "-count=1"); err != nil {
+		t.Fatalf("Unexpected error: %s (stderr = %s)", err, stderr.String())
+	}
+	if output := stdout.String(); !strings.Contains(output, "wrapped by two words") {
+		t.Errorf("Expected the test to be run by the wrapper: %s", output)
+	}
+}